@@ -42,12 +42,18 @@ func main() {
 	var dir string
 	var debugFlag bool
 	var customFuncsCSV string
+	var convertPkgsCSV string
+	var compose int
+	var nilPolicy string
 
 	flag.StringVar(&interfacesCSV, "interface", "", "Comma-separated list of mapper interface names to implement (required)")
 	flag.StringVar(&output, "output", "graft_gen.go", "Output filename for generated code")
 	flag.StringVar(&dir, "dir", ".", "Directory to scan for interface definitions (relative to current directory)")
 	flag.BoolVar(&debugFlag, "debug", false, "Emit debug comments linking generated code to template nodes")
 	flag.StringVar(&customFuncsCSV, "custom_funcs", "", "Comma-separated list of custom mapping function names")
+	flag.StringVar(&convertPkgsCSV, "convert_pkgs", "", "Comma-separated list of import paths whose exported conversion functions are used as well")
+	flag.StringVar(&nilPolicy, "nil", "", "Policy for nil and empty collections: keep (default), empty (nil becomes empty) or nil (empty becomes nil)")
+	flag.IntVar(&compose, "compose", 0, "Maximum number of registered converters (at least 2) chained for a conversion nothing else maps (0 disables)")
 
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage: %s [flags]\n", os.Args[0])
@@ -81,6 +87,16 @@ func main() {
 		}
 	}
 
+	var convertPkgs []string
+	if convertPkgsCSV != "" {
+		for p := range strings.SplitSeq(convertPkgsCSV, ",") {
+			p = strings.TrimSpace(p)
+			if p != "" {
+				convertPkgs = append(convertPkgs, p)
+			}
+		}
+	}
+
 	// build a simplified canonical command representation instead of raw argv (which may include build cache paths)
 	cmdParts := []string{"graftgen", "-interface=" + strings.Join(interfaces, ","), "-output=" + output}
 	if dir != "." {
@@ -92,6 +108,15 @@ func main() {
 	if len(customFuncs) > 0 {
 		cmdParts = append(cmdParts, "-custom_funcs="+strings.Join(customFuncs, ","))
 	}
+	if len(convertPkgs) > 0 {
+		cmdParts = append(cmdParts, "-convert_pkgs="+strings.Join(convertPkgs, ","))
+	}
+	if compose > 0 {
+		cmdParts = append(cmdParts, fmt.Sprintf("-compose=%d", compose))
+	}
+	if nilPolicy != "" {
+		cmdParts = append(cmdParts, "-nil="+nilPolicy)
+	}
 	displayCmd := strings.Join(cmdParts, " ")
 	buildVersion := deriveVersion()
 
@@ -101,6 +126,9 @@ func main() {
 		Output:      output,
 		Debug:       debugFlag,
 		CustomFuncs: customFuncs,
+		ConvertPkgs: convertPkgs,
+		Compose:     compose,
+		NilPolicy:   nilPolicy,
 		Command:     displayCmd,
 		Version:     buildVersion,
 	}
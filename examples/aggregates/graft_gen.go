@@ -0,0 +1,42 @@
+// Code generated by graftgen (version devel); DO NOT EDIT.
+
+// Source interfaces: CartMapper
+// Command: graftgen -interface=CartMapper -output=graft_gen.go
+
+package aggregates
+
+// map_Cart_to_CartDTO maps a value of type Cart to CartDTO.
+func map_Cart_to_CartDTO(in Cart) (CartDTO, error) {
+	var dst CartDTO
+	dst.LineCount = int64(len(in.Lines))
+
+	var tmp Money
+	for _, v := range in.Lines {
+		tmp = AddLine(tmp, v)
+	}
+	dst.Total = tmp
+	var tmp1 int64
+	for _, v := range in.Held {
+		next, err := AddQty(tmp1, v)
+		if err != nil {
+			return dst, err
+		}
+		tmp1 = next
+	}
+	dst.HeldQty = tmp1
+	if in.Customer != nil {
+		dst.TagCount = len(in.Customer.Tags)
+	}
+	return dst, nil
+}
+
+// cartMapperImpl is the generated implementation of CartMapper.
+type cartMapperImpl struct{}
+
+// NewCartMapper returns a new CartMapper implementation.
+func NewCartMapper() CartMapper { return &cartMapperImpl{} }
+
+// ToDTO maps c to the destination type.
+func (m *cartMapperImpl) ToDTO(c Cart) (CartDTO, error) {
+	return map_Cart_to_CartDTO(c)
+}
@@ -0,0 +1,47 @@
+package aggregates
+
+import "errors"
+
+//go:generate go run ../../cmd/graftgen -interface=CartMapper -output=graft_gen.go
+
+// Money is an amount in cents.
+type Money int64
+
+type Line struct {
+	SKU   string
+	Qty   int
+	Price Money
+}
+
+// AddLine adds a line's price to a running total.
+func AddLine(total Money, l Line) Money { return total + Money(l.Qty)*l.Price }
+
+// AddQty sums quantities, rejecting negative ones.
+func AddQty(sum int64, l *Line) (int64, error) {
+	if l.Qty < 0 {
+		return 0, errors.New("negative quantity")
+	}
+	return sum + int64(l.Qty), nil
+}
+
+type Customer struct {
+	Name string
+	Tags map[string]string
+}
+
+type Cart struct {
+	Lines    []Line
+	Held     []*Line
+	Customer *Customer
+}
+
+type CartDTO struct {
+	LineCount int64 `mapagg:"len(Lines)"`
+	Total     Money `mapagg:"AddLine(Lines)"`
+	HeldQty   int64 `mapagg:"AddQty(Held)"`
+	TagCount  int   `mapagg:"len(Customer.Tags)"`
+}
+
+type CartMapper interface {
+	ToDTO(c Cart) (CartDTO, error)
+}
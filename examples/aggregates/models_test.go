@@ -0,0 +1,32 @@
+package aggregates
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregates(t *testing.T) {
+	m := NewCartMapper()
+
+	t.Run("derives counts and totals", func(t *testing.T) {
+		out, err := m.ToDTO(Cart{
+			Lines:    []Line{{SKU: "a", Qty: 2, Price: 150}, {SKU: "b", Qty: 1, Price: 99}},
+			Held:     []*Line{{Qty: 3}, {Qty: 4}},
+			Customer: &Customer{Tags: map[string]string{"tier": "gold"}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, CartDTO{LineCount: 2, Total: 399, HeldQty: 7, TagCount: 1}, out)
+	})
+
+	t.Run("empty sources aggregate to zero", func(t *testing.T) {
+		out, err := m.ToDTO(Cart{})
+		require.NoError(t, err)
+		require.Equal(t, CartDTO{}, out)
+	})
+
+	t.Run("reducer errors are returned", func(t *testing.T) {
+		_, err := m.ToDTO(Cart{Held: []*Line{{Qty: -1}}})
+		require.EqualError(t, err, "negative quantity")
+	})
+}
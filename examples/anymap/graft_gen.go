@@ -0,0 +1,232 @@
+// Code generated by graftgen (version devel); DO NOT EDIT.
+
+// Source interfaces: UserMapper
+// Command: graftgen -interface=UserMapper -output=graft_gen.go
+
+package anymap
+
+import (
+	"fmt"
+	"time"
+)
+
+// map_Map_string_To_any_to_User_json maps a value of type map[string]any to User.
+func map_Map_string_To_any_to_User_json(in map[string]any) (User, error) {
+	var dst User
+	if v, ok := in["id"]; ok && v != nil {
+		x, ok := v.(int)
+		if !ok {
+			return dst, fmt.Errorf("key %q: want int, have %T", "id", v)
+		}
+		dst.ID = x
+	}
+	if v, ok := in["name"]; ok && v != nil {
+		x, ok := v.(string)
+		if !ok {
+			return dst, fmt.Errorf("key %q: want string, have %T", "name", v)
+		}
+		dst.Name = x
+	}
+	if v, ok := in["email"]; ok && v != nil {
+		x, ok := v.(string)
+		if !ok {
+			return dst, fmt.Errorf("key %q: want string, have %T", "email", v)
+		}
+		dst.Email = x
+	}
+	if v, ok := in["tags"]; ok && v != nil {
+		x, ok := v.([]string)
+		if !ok {
+			return dst, fmt.Errorf("key %q: want []string, have %T", "tags", v)
+		}
+		dst.Tags = x
+	}
+	if v, ok := in["address"]; ok && v != nil {
+		x, ok := v.(map[string]any)
+		if !ok {
+			return dst, fmt.Errorf("key %q: want map[string]any, have %T", "address", v)
+		}
+		mapped, err := map_Map_string_To_any_to_Address_json(x)
+		if err != nil {
+			return dst, fmt.Errorf("key %q: %w", "address", err)
+		}
+		dst.Address = mapped
+	}
+	if v, ok := in["manager"]; ok && v != nil {
+		x, ok := v.(map[string]any)
+		if !ok {
+			return dst, fmt.Errorf("key %q: want map[string]any, have %T", "manager", v)
+		}
+		mapped, err := map_Map_string_To_any_to_Ptr_Contact_json(x)
+		if err != nil {
+			return dst, fmt.Errorf("key %q: %w", "manager", err)
+		}
+		dst.Manager = mapped
+	}
+	if v, ok := in["Nickname"]; ok && v != nil {
+		x, ok := v.(string)
+		if !ok {
+			return dst, fmt.Errorf("key %q: want string, have %T", "Nickname", v)
+		}
+		dst.Nickname = x
+	}
+	if v, ok := in["joined_at"]; ok && v != nil {
+		x, ok := v.(time.Time)
+		if !ok {
+			return dst, fmt.Errorf("key %q: want time.Time, have %T", "joined_at", v)
+		}
+		dst.JoinedAt = x
+	}
+	return dst, nil
+}
+
+// map_Record_to_Event maps a value of type Record to Event.
+func map_Record_to_Event(in Record) (Event, error) {
+	var dst Event
+	dst.Name = in.Name
+	tmp, err := map_Map_string_To_any_to_Attrs(in.Attrs)
+	if err != nil {
+		return dst, err
+	}
+	dst.Attrs = tmp
+
+	return dst, nil
+}
+
+// map_User_to_Map_string_To_any_json maps a value of type User to map[string]any.
+func map_User_to_Map_string_To_any_json(in User) map[string]any {
+	var dst map[string]any
+	dst = make(map[string]any, 9)
+	dst["id"] = in.ID
+	dst["name"] = in.Name
+	if in.Email != "" {
+		dst["email"] = in.Email
+	}
+	dst["tags"] = in.Tags
+	dst["address"] = map_Address_to_Map_string_To_any_json(in.Address)
+
+	if in.Manager != nil {
+		dst["manager"] = map_Ptr_Contact_to_Map_string_To_any_json(in.Manager)
+
+	}
+	dst["Nickname"] = in.Nickname
+	dst["joined_at"] = in.JoinedAt
+	return dst
+}
+
+// map_Event_to_Record maps a value of type Event to Record.
+func map_Event_to_Record(in Event) Record {
+	var dst Record
+	dst.Name = in.Name
+	dst.Attrs = map_Attrs_to_Map_string_To_any(in.Attrs)
+
+	return dst
+}
+
+// map_Map_string_To_any_to_Address_json maps a value of type map[string]any to Address.
+func map_Map_string_To_any_to_Address_json(in map[string]any) (Address, error) {
+	var dst Address
+	if v, ok := in["street"]; ok && v != nil {
+		x, ok := v.(string)
+		if !ok {
+			return dst, fmt.Errorf("key %q: want string, have %T", "street", v)
+		}
+		dst.Street = x
+	}
+	if v, ok := in["city"]; ok && v != nil {
+		x, ok := v.(string)
+		if !ok {
+			return dst, fmt.Errorf("key %q: want string, have %T", "city", v)
+		}
+		dst.City = x
+	}
+	return dst, nil
+}
+
+// map_Map_string_To_any_to_Ptr_Contact_json maps a value of type map[string]any to *Contact.
+func map_Map_string_To_any_to_Ptr_Contact_json(in map[string]any) (*Contact, error) {
+	dst := new(Contact)
+	if v, ok := in["name"]; ok && v != nil {
+		x, ok := v.(string)
+		if !ok {
+			return dst, fmt.Errorf("key %q: want string, have %T", "name", v)
+		}
+		dst.Name = x
+	}
+	return dst, nil
+}
+
+// map_Map_string_To_any_to_Attrs maps a value of type map[string]any to Attrs.
+func map_Map_string_To_any_to_Attrs(in map[string]any) (Attrs, error) {
+	var dst Attrs
+	if v, ok := in["Source"]; ok && v != nil {
+		x, ok := v.(string)
+		if !ok {
+			return dst, fmt.Errorf("key %q: want string, have %T", "Source", v)
+		}
+		dst.Source = x
+	}
+	if v, ok := in["Retry"]; ok && v != nil {
+		x, ok := v.(bool)
+		if !ok {
+			return dst, fmt.Errorf("key %q: want bool, have %T", "Retry", v)
+		}
+		dst.Retry = x
+	}
+	return dst, nil
+}
+
+// map_Address_to_Map_string_To_any_json maps a value of type Address to map[string]any.
+func map_Address_to_Map_string_To_any_json(in Address) map[string]any {
+	var dst map[string]any
+	dst = make(map[string]any, 2)
+	dst["street"] = in.Street
+	dst["city"] = in.City
+	return dst
+}
+
+// map_Ptr_Contact_to_Map_string_To_any_json maps a value of type *Contact to map[string]any.
+func map_Ptr_Contact_to_Map_string_To_any_json(in *Contact) map[string]any {
+	if in == nil {
+		return nil
+	}
+	var dst map[string]any
+	dst = make(map[string]any, 1)
+	dst["name"] = in.Name
+	return dst
+}
+
+// map_Attrs_to_Map_string_To_any maps a value of type Attrs to map[string]any.
+func map_Attrs_to_Map_string_To_any(in Attrs) map[string]any {
+	var dst map[string]any
+	dst = make(map[string]any, 2)
+	dst["Source"] = in.Source
+	dst["Retry"] = in.Retry
+	return dst
+}
+
+// userMapperImpl is the generated implementation of UserMapper.
+type userMapperImpl struct{}
+
+// NewUserMapper returns a new UserMapper implementation.
+func NewUserMapper() UserMapper { return &userMapperImpl{} }
+
+// FromMap maps p0 to the destination type.
+func (m *userMapperImpl) FromMap(p0 map[string]any) (User, error) {
+	return map_Map_string_To_any_to_User_json(p0)
+}
+
+// FromRecord maps p0 to the destination type.
+func (m *userMapperImpl) FromRecord(p0 Record) (Event, error) {
+	return map_Record_to_Event(p0)
+}
+
+// ToMap maps p0 to the destination type.
+func (m *userMapperImpl) ToMap(p0 User) map[string]any {
+	return map_User_to_Map_string_To_any_json(p0)
+}
+
+// ToRecord maps p0 to the destination type.
+func (m *userMapperImpl) ToRecord(p0 Event) Record {
+	return map_Event_to_Record(p0)
+}
@@ -0,0 +1,52 @@
+package anymap
+
+import "time"
+
+//go:generate go run ../../cmd/graftgen -interface=UserMapper -output=graft_gen.go
+
+type Address struct {
+	Street string `json:"street"`
+	City   string `json:"city"`
+}
+
+type Contact struct {
+	Name string `json:"name"`
+}
+
+type User struct {
+	ID       int      `json:"id"`
+	Name     string   `json:"name"`
+	Email    string   `json:"email,omitempty"`
+	Tags     []string `json:"tags"`
+	Address  Address  `json:"address"`
+	Manager  *Contact `json:"manager"`
+	Password string   `json:"-"`
+	Nickname string
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+// Event carries its attributes as a struct; Record keeps them as a generic
+// map keyed by field name.
+type Event struct {
+	Name  string
+	Attrs Attrs
+}
+
+type Attrs struct {
+	Source string
+	Retry  bool
+}
+
+type Record struct {
+	Name  string
+	Attrs map[string]any
+}
+
+type UserMapper interface {
+	//graft:keytag json
+	ToMap(User) map[string]any
+	//graft:keytag json
+	FromMap(map[string]any) (User, error)
+	ToRecord(Event) Record
+	FromRecord(Record) (Event, error)
+}
@@ -0,0 +1,93 @@
+package anymap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserMapper(t *testing.T) {
+	m := NewUserMapper()
+	joined := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	t.Run("ToMap keys fields by json tag", func(t *testing.T) {
+		out := m.ToMap(User{
+			ID:       7,
+			Name:     "Ada",
+			Tags:     []string{"admin"},
+			Address:  Address{Street: "1 Main St", City: "Springfield"},
+			Manager:  &Contact{Name: "Grace"},
+			Password: "secret",
+			Nickname: "ada",
+			JoinedAt: joined,
+		})
+		require.Equal(t, map[string]any{
+			"id":        7,
+			"name":      "Ada",
+			"tags":      []string{"admin"},
+			"address":   map[string]any{"street": "1 Main St", "city": "Springfield"},
+			"manager":   map[string]any{"name": "Grace"},
+			"Nickname":  "ada",
+			"joined_at": joined,
+		}, out)
+	})
+
+	t.Run("ToMap omits empty and nil fields", func(t *testing.T) {
+		out := m.ToMap(User{ID: 1, Email: "ada@example.com"})
+		require.Equal(t, "ada@example.com", out["email"])
+		require.NotContains(t, out, "manager")
+		require.NotContains(t, out, "Password")
+	})
+
+	t.Run("FromMap round trips", func(t *testing.T) {
+		in := User{
+			ID:       7,
+			Name:     "Ada",
+			Email:    "ada@example.com",
+			Tags:     []string{"admin"},
+			Address:  Address{Street: "1 Main St", City: "Springfield"},
+			Manager:  &Contact{Name: "Grace"},
+			JoinedAt: joined,
+		}
+		out, err := m.FromMap(m.ToMap(in))
+		require.NoError(t, err)
+		require.Equal(t, in, out)
+	})
+
+	t.Run("FromMap leaves missing and nil keys zero", func(t *testing.T) {
+		out, err := m.FromMap(map[string]any{"name": "Ada", "manager": nil})
+		require.NoError(t, err)
+		require.Equal(t, User{Name: "Ada"}, out)
+	})
+
+	t.Run("FromMap reports mismatched types by key", func(t *testing.T) {
+		_, err := m.FromMap(map[string]any{"id": "7"})
+		require.EqualError(t, err, `key "id": want int, have string`)
+	})
+
+	t.Run("FromMap asserts opaque structs as they are", func(t *testing.T) {
+		_, err := m.FromMap(map[string]any{"joined_at": "2024-03-01"})
+		require.EqualError(t, err, `key "joined_at": want time.Time, have string`)
+	})
+
+	t.Run("FromMap reports nested keys", func(t *testing.T) {
+		_, err := m.FromMap(map[string]any{"address": map[string]any{"city": 42}})
+		require.EqualError(t, err, `key "address": key "city": want string, have int`)
+
+		_, err = m.FromMap(map[string]any{"address": "Springfield"})
+		require.EqualError(t, err, `key "address": want map[string]any, have string`)
+	})
+
+	t.Run("struct fields use field names without a key tag", func(t *testing.T) {
+		rec := m.ToRecord(Event{Name: "sync", Attrs: Attrs{Source: "cron", Retry: true}})
+		require.Equal(t, Record{Name: "sync", Attrs: map[string]any{"Source": "cron", "Retry": true}}, rec)
+
+		ev, err := m.FromRecord(rec)
+		require.NoError(t, err)
+		require.Equal(t, Event{Name: "sync", Attrs: Attrs{Source: "cron", Retry: true}}, ev)
+
+		_, err = m.FromRecord(Record{Attrs: map[string]any{"Retry": "yes"}})
+		require.EqualError(t, err, `key "Retry": want bool, have string`)
+	})
+}
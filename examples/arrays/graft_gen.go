@@ -0,0 +1,51 @@
+// Code generated by graftgen (version devel); DO NOT EDIT.
+
+// Source interfaces: PointMapper
+// Command: graftgen -interface=PointMapper -output=graft_gen.go
+
+package arrays
+
+import "fmt"
+
+// map_Point_to_PointDTO maps a value of type Point to PointDTO.
+func map_Point_to_PointDTO(in Point) (PointDTO, error) {
+	var dst PointDTO
+	dst.Coords = make([]float64, len(in.Coords))
+	for i := range in.Coords {
+		dst.Coords[i] = in.Coords[i]
+	}
+	if len(in.Tags) != 0 && len(in.Tags) != 2 {
+		return dst, fmt.Errorf("%d elements mapping in.Tags do not fit [2]string", len(in.Tags))
+	}
+	for i := range in.Tags {
+		dst.Tags[i] = in.Tags[i]
+	}
+	for i := range 2 {
+		dst.Labels[i] = LabelText(in.Labels[i])
+
+	}
+	if len(in.History) > 3 {
+		return dst, fmt.Errorf("%d elements mapping in.History do not fit [3]int", len(in.History))
+	}
+	for i := range in.History {
+		dst.History[i] = in.History[i]
+	}
+	for i := range in.Samples {
+		dst.Samples[i] = in.Samples[i]
+	}
+	for i := range min(len(in.Recent), 2) {
+		dst.Recent[i] = in.Recent[i]
+	}
+	return dst, nil
+}
+
+// pointMapperImpl is the generated implementation of PointMapper.
+type pointMapperImpl struct{}
+
+// NewPointMapper returns a new PointMapper implementation.
+func NewPointMapper() PointMapper { return &pointMapperImpl{} }
+
+// ToDTO maps p0 to the destination type.
+func (m *pointMapperImpl) ToDTO(p0 Point) (PointDTO, error) {
+	return map_Point_to_PointDTO(p0)
+}
@@ -0,0 +1,33 @@
+package arrays
+
+import "strconv"
+
+//go:generate go run ../../cmd/graftgen -interface=PointMapper -output=graft_gen.go
+
+// Label is stored as a string on the wire.
+type Label int
+
+// LabelText formats a label.
+func LabelText(l Label) string { return "L" + strconv.Itoa(int(l)) }
+
+type Point struct {
+	Coords  [3]float64
+	Tags    []string
+	Labels  [4]Label
+	History []int
+	Samples [5]int
+	Recent  []int
+}
+
+type PointDTO struct {
+	Coords  []float64
+	Tags    [2]string
+	Labels  [2]string `maplen:"truncate"`
+	History [3]int    `maplen:"pad"`
+	Samples [8]int    `maplen:"pad"`
+	Recent  [2]int    `maplen:"truncate"`
+}
+
+type PointMapper interface {
+	ToDTO(Point) (PointDTO, error)
+}
@@ -0,0 +1,50 @@
+package arrays
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestArrays(t *testing.T) {
+	m := NewPointMapper()
+
+	t.Run("maps between slices and arrays of other lengths", func(t *testing.T) {
+		out, err := m.ToDTO(Point{
+			Coords:  [3]float64{1, 2.5, 3},
+			Tags:    []string{"a", "b"},
+			Labels:  [4]Label{1, 2, 3, 4},
+			History: []int{7},
+			Samples: [5]int{1, 2, 3, 4, 5},
+			Recent:  []int{9, 8, 7},
+		})
+		require.NoError(t, err)
+		require.Equal(t, []float64{1, 2.5, 3}, out.Coords)
+		require.Equal(t, [2]string{"a", "b"}, out.Tags)
+		require.Equal(t, [2]string{"L1", "L2"}, out.Labels)
+		require.Equal(t, [3]int{7, 0, 0}, out.History)
+		require.Equal(t, [8]int{1, 2, 3, 4, 5}, out.Samples)
+		require.Equal(t, [2]int{9, 8}, out.Recent)
+	})
+
+	t.Run("short slices are padded or truncated", func(t *testing.T) {
+		out, err := m.ToDTO(Point{Tags: []string{"a", "b"}, Recent: []int{1}})
+		require.NoError(t, err)
+		require.Equal(t, [3]int{}, out.History)
+		require.Equal(t, [2]int{1, 0}, out.Recent)
+	})
+
+	t.Run("nil slices map to zero arrays", func(t *testing.T) {
+		out, err := m.ToDTO(Point{})
+		require.NoError(t, err)
+		require.Equal(t, [2]string{}, out.Tags)
+	})
+
+	t.Run("length mismatches fail", func(t *testing.T) {
+		_, err := m.ToDTO(Point{Tags: []string{"a"}})
+		require.ErrorContains(t, err, "1 elements mapping in.Tags do not fit [2]string")
+
+		_, err = m.ToDTO(Point{Tags: []string{"a", "b"}, History: []int{1, 2, 3, 4}})
+		require.ErrorContains(t, err, "4 elements mapping in.History do not fit [3]int")
+	})
+}
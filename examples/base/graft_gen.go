@@ -0,0 +1,55 @@
+// Code generated by graftgen (version devel); DO NOT EDIT.
+
+// Source interfaces: UserMapper
+// Command: graftgen -interface=UserMapper -output=graft_gen.go
+
+package base
+
+// map_User_to_UserDTO maps a value of type User to UserDTO.
+func map_User_to_UserDTO(base *AddressFormatter, in User) UserDTO {
+	var dst UserDTO
+	dst.Name = in.Name
+	dst.Home = base.AddressToDTO(in.Home)
+
+	if in.Work != nil {
+		tmp := base.AddressToDTO(*in.Work)
+		dst.Work = &tmp
+	} else {
+		dst.Work = nil
+	}
+	if in.Past != nil {
+		dst.Past = make([]AddressDTO, len(in.Past))
+		for i, v := range in.Past { // v used by child nodes
+			var mapped AddressDTO
+			mapped = base.AddressToDTO(v)
+
+			dst.Past[i] = mapped
+		}
+	} else {
+		dst.Past = nil
+	}
+	return dst
+}
+
+// userMapperImpl is the generated implementation of UserMapper.
+type userMapperImpl struct {
+	*AddressFormatter
+}
+
+// NewUserMapper returns a new UserMapper implementation completing the
+// hand-written *AddressFormatter.
+func NewUserMapper(base *AddressFormatter) UserMapper { return &userMapperImpl{AddressFormatter: base} }
+
+// ToCard maps u to the destination type.
+func (m *userMapperImpl) ToCard(u User, title string) Card {
+	var dst Card
+	dst.Title = title
+	dst.Home = m.AddressToDTO(u.Home)
+
+	return dst
+}
+
+// UserToDTO maps u to the destination type.
+func (m *userMapperImpl) UserToDTO(u User) UserDTO {
+	return map_User_to_UserDTO(m.AddressFormatter, u)
+}
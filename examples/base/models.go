@@ -0,0 +1,50 @@
+package base
+
+import "strings"
+
+//go:generate go run ../../cmd/graftgen -interface=UserMapper -output=graft_gen.go
+
+type Address struct {
+	Street string
+	City   string
+}
+
+type AddressDTO struct {
+	Line string
+}
+
+type User struct {
+	Name string
+	Home Address
+	Work *Address
+	Past []Address
+}
+
+type UserDTO struct {
+	Name string
+	Home AddressDTO
+	Work *AddressDTO
+	Past []AddressDTO
+}
+
+type Card struct {
+	Title string
+	Home  AddressDTO
+}
+
+// AddressFormatter is the hand-written part of UserMapper.
+type AddressFormatter struct {
+	Sep string
+}
+
+// AddressToDTO joins the address parts into a single line.
+func (f *AddressFormatter) AddressToDTO(a Address) AddressDTO {
+	return AddressDTO{Line: strings.Join([]string{a.Street, a.City}, f.Sep)}
+}
+
+//graft:base *AddressFormatter
+type UserMapper interface {
+	UserToDTO(u User) UserDTO
+	AddressToDTO(a Address) AddressDTO
+	ToCard(u User, title string) Card
+}
@@ -0,0 +1,30 @@
+package base
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBase(t *testing.T) {
+	m := NewUserMapper(&AddressFormatter{Sep: ", "})
+	home := Address{Street: "1 Main St", City: "Springfield"}
+
+	t.Run("hand-written methods are kept", func(t *testing.T) {
+		require.Equal(t, AddressDTO{Line: "1 Main St, Springfield"}, m.AddressToDTO(home))
+	})
+
+	t.Run("nested fields route through the base", func(t *testing.T) {
+		work := Address{Street: "2 Elm St", City: "Shelbyville"}
+		out := m.UserToDTO(User{Name: "ann", Home: home, Work: &work, Past: []Address{work}})
+		require.Equal(t, "ann", out.Name)
+		require.Equal(t, "1 Main St, Springfield", out.Home.Line)
+		require.Equal(t, &AddressDTO{Line: "2 Elm St, Shelbyville"}, out.Work)
+		require.Equal(t, []AddressDTO{{Line: "2 Elm St, Shelbyville"}}, out.Past)
+	})
+
+	t.Run("base methods in method bodies", func(t *testing.T) {
+		out := m.ToCard(User{Home: home}, "home")
+		require.Equal(t, Card{Title: "home", Home: AddressDTO{Line: "1 Main St, Springfield"}}, out)
+	})
+}
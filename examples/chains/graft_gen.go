@@ -0,0 +1,101 @@
+// Code generated by graftgen (version devel); DO NOT EDIT.
+
+// Source interfaces: SignupMapper
+// Command: graftgen -interface=SignupMapper -output=graft_gen.go
+
+package chains
+
+import (
+	"strconv"
+	"strings"
+)
+
+// map_Signup_to_Account maps a value of type Signup to Account.
+func map_Signup_to_Account(in Signup) (Account, error) {
+	var dst Account
+	tmp, err := NormalizeEmail(strings.ToLower(strings.TrimSpace(in.Email)))
+	if err != nil {
+		return dst, err
+	}
+	dst.Email = Email(tmp)
+	dst.Handle = strings.ToLower(strings.TrimSpace(in.Handle))
+	tmp1, err := strconv.Atoi(strings.TrimSpace(in.Age))
+	if err != nil {
+		return dst, err
+	}
+	dst.Age = tmp1
+	if in.Tags != nil {
+		dst.Tags = make([]string, len(in.Tags))
+		for i, v := range in.Tags { // v used by child nodes
+			var mapped string
+			mapped = strings.ToLower(strings.TrimSpace(v))
+			dst.Tags[i] = mapped
+		}
+	} else {
+		dst.Tags = nil
+	}
+	if in.Labels != nil {
+		dst.Labels = make(map[string]string, len(in.Labels))
+		for k, v := range in.Labels { // k,v used by child nodes
+			var mapped string
+			mapped = strings.TrimSpace(v)
+
+			dst.Labels[k] = mapped
+		}
+	} else {
+		dst.Labels = nil
+	}
+	// no source field for Code
+	return dst, nil
+}
+
+// signupMapperImpl is the generated implementation of SignupMapper.
+type signupMapperImpl struct{}
+
+// NewSignupMapper returns a new SignupMapper implementation.
+func NewSignupMapper() SignupMapper { return &signupMapperImpl{} }
+
+// ToAccount maps s to the destination type.
+func (m *signupMapperImpl) ToAccount(s Signup) (Account, error) {
+	return map_Signup_to_Account(s)
+}
+
+// ToInvitedAccount maps s to the destination type.
+func (m *signupMapperImpl) ToInvitedAccount(s Signup, invite Invite) (Account, error) {
+	var dst Account
+	tmp, err := NormalizeEmail(strings.ToLower(strings.TrimSpace(s.Email)))
+	if err != nil {
+		return dst, err
+	}
+	dst.Email = Email(tmp)
+	dst.Handle = strings.ToLower(strings.TrimSpace(s.Handle))
+	tmp1, err := strconv.Atoi(strings.TrimSpace(s.Age))
+	if err != nil {
+		return dst, err
+	}
+	dst.Age = tmp1
+	if s.Tags != nil {
+		dst.Tags = make([]string, len(s.Tags))
+		for i, v := range s.Tags { // v used by child nodes
+			var mapped string
+			mapped = strings.ToLower(strings.TrimSpace(v))
+			dst.Tags[i] = mapped
+		}
+	} else {
+		dst.Tags = nil
+	}
+	if s.Labels != nil {
+		dst.Labels = make(map[string]string, len(s.Labels))
+		for k, v := range s.Labels { // k,v used by child nodes
+			var mapped string
+			mapped = strings.TrimSpace(v)
+
+			dst.Labels[k] = mapped
+		}
+	} else {
+		dst.Labels = nil
+	}
+	dst.Code = strings.ToUpper(invite.Code)
+
+	return dst, nil
+}
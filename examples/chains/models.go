@@ -0,0 +1,51 @@
+package chains
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+//go:generate go run ../../cmd/graftgen -interface=SignupMapper -output=graft_gen.go
+
+type Email string
+
+type Signup struct {
+	Email  string
+	Handle string
+	Age    string
+	Tags   []string
+	Labels map[string]string
+}
+
+type Invite struct {
+	Code string
+}
+
+type Account struct {
+	Email  Email             `mapfn:"strings.TrimSpace,strings.ToLower,NormalizeEmail"`
+	Handle string            `mapfn:"strings.TrimSpace,strings.ToLower"`
+	Age    int               `mapfn:"strings.TrimSpace,strconv.Atoi"`
+	Tags   []string          `mapfn:"strings.TrimSpace,strings.ToLower"`
+	Labels map[string]string `mapfn:"strings.TrimSpace"`
+	Code   string            `mapsrc:"invite.Code" mapfn:"strings.ToUpper"`
+}
+
+var ErrInvalidEmail = errors.New("invalid email")
+
+// NormalizeEmail validates an address that is already trimmed and lowercased.
+func NormalizeEmail(s string) (string, error) {
+	if !strings.Contains(s, "@") {
+		return "", ErrInvalidEmail
+	}
+	return s, nil
+}
+
+func (a Account) String() string {
+	return string(a.Email) + " (" + strconv.Itoa(a.Age) + ")"
+}
+
+type SignupMapper interface {
+	ToAccount(s Signup) (Account, error)
+	ToInvitedAccount(s Signup, invite Invite) (Account, error)
+}
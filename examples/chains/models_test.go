@@ -0,0 +1,50 @@
+package chains
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChains(t *testing.T) {
+	m := NewSignupMapper()
+	in := Signup{
+		Email:  "  Ann@Example.COM ",
+		Handle: " Ann ",
+		Age:    " 42 ",
+		Tags:   []string{" Go", "RUST "},
+		Labels: map[string]string{"team": " core "},
+	}
+
+	t.Run("steps run in order", func(t *testing.T) {
+		out, err := m.ToAccount(in)
+		require.NoError(t, err)
+		require.Equal(t, Email("ann@example.com"), out.Email)
+		require.Equal(t, "ann", out.Handle)
+		require.Equal(t, 42, out.Age)
+		require.Equal(t, []string{"go", "rust"}, out.Tags)
+		require.Equal(t, map[string]string{"team": "core"}, out.Labels)
+	})
+
+	t.Run("failing step aborts the mapping", func(t *testing.T) {
+		bad := in
+		bad.Email = "nobody"
+		_, err := m.ToAccount(bad)
+		require.ErrorIs(t, err, ErrInvalidEmail)
+
+		bad = in
+		bad.Age = "x"
+		_, err = m.ToAccount(bad)
+		var numErr *strconv.NumError
+		require.ErrorAs(t, err, &numErr)
+	})
+
+	t.Run("chains in method bodies", func(t *testing.T) {
+		out, err := m.ToInvitedAccount(in, Invite{Code: "abc"})
+		require.NoError(t, err)
+		require.Equal(t, "ABC", out.Code)
+		require.Equal(t, Email("ann@example.com"), out.Email)
+		require.Equal(t, []string{"go", "rust"}, out.Tags)
+	})
+}
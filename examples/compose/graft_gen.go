@@ -0,0 +1,71 @@
+// Code generated by graftgen (version devel); DO NOT EDIT.
+
+// Source interfaces: TicketMapper
+// Command: graftgen -interface=TicketMapper -output=graft_gen.go -compose=3
+
+package compose
+
+// map_Reading_to_ReadingDTO maps a value of type Reading to ReadingDTO.
+func map_Reading_to_ReadingDTO(in Reading) ReadingDTO {
+	var dst ReadingDTO
+	dst.Temp = KToF(CToK(in.Temp))
+	return dst
+}
+
+// map_User_to_UserDTO maps a value of type User to UserDTO.
+func map_User_to_UserDTO(in User) UserDTO {
+	var dst UserDTO
+	dst.Name = in.Name
+	return dst
+}
+
+// ticketMapperImpl is the generated implementation of TicketMapper.
+type ticketMapperImpl struct{}
+
+// NewTicketMapper returns a new TicketMapper implementation.
+func NewTicketMapper() TicketMapper { return &ticketMapperImpl{} }
+
+// ToReading maps r to the destination type.
+func (m *ticketMapperImpl) ToReading(r Reading) ReadingDTO {
+	return map_Reading_to_ReadingDTO(r)
+}
+
+// ToTicket maps t to the destination type.
+func (m *ticketMapperImpl) ToTicket(t Ticket, note string) (TicketDTO, error) {
+	var dst TicketDTO
+	dst.Title = t.Title
+	tmp, err := ParseID(t.Owner)
+	if err != nil {
+		return dst, err
+	}
+	tmp1, err := UserByID(tmp)
+	if err != nil {
+		return dst, err
+	}
+	dst.Owner = m.ToUser(tmp1)
+	if t.Watchers != nil {
+		dst.Watchers = make([]UserDTO, len(t.Watchers))
+		for i, v := range t.Watchers { // v used by child nodes
+			var mapped UserDTO
+			tmp2, err := ParseID(v)
+			if err != nil {
+				return dst, err
+			}
+			tmp3, err := UserByID(tmp2)
+			if err != nil {
+				return dst, err
+			}
+			mapped = m.ToUser(tmp3)
+			dst.Watchers[i] = mapped
+		}
+	} else {
+		dst.Watchers = nil
+	}
+	dst.Note = note
+	return dst, nil
+}
+
+// ToUser maps u to the destination type.
+func (m *ticketMapperImpl) ToUser(u User) UserDTO {
+	return map_User_to_UserDTO(u)
+}
@@ -0,0 +1,73 @@
+package compose
+
+import (
+	"errors"
+	"strconv"
+)
+
+//go:generate go run ../../cmd/graftgen -interface=TicketMapper -compose=3 -output=graft_gen.go
+
+var ErrUnknownUser = errors.New("unknown user")
+
+type ID int
+
+type User struct {
+	ID   ID
+	Name string
+}
+
+type UserDTO struct {
+	Name string
+}
+
+// Directory holds the users known to UserByID.
+var Directory = map[ID]User{}
+
+// ParseID parses a user reference.
+func ParseID(s string) (ID, error) {
+	n, err := strconv.Atoi(s)
+	return ID(n), err
+}
+
+// UserByID looks a user up in the Directory.
+func UserByID(id ID) (User, error) {
+	u, ok := Directory[id]
+	if !ok {
+		return User{}, ErrUnknownUser
+	}
+	return u, nil
+}
+
+type Celsius float64
+type Kelvin float64
+type Fahrenheit float64
+
+func CToK(c Celsius) Kelvin    { return Kelvin(c + 273.15) }
+func KToF(k Kelvin) Fahrenheit { return Fahrenheit(k*9/5 - 459.67) }
+
+type Ticket struct {
+	Title    string
+	Owner    string
+	Watchers []string
+}
+
+type TicketDTO struct {
+	Title    string
+	Owner    UserDTO
+	Watchers []UserDTO
+	Note     string
+}
+
+type Reading struct {
+	Temp Celsius
+}
+
+type ReadingDTO struct {
+	Temp Fahrenheit
+}
+
+type TicketMapper interface {
+	ToUser(u User) UserDTO
+	ToTicket(t Ticket, note string) (TicketDTO, error)
+	ToReading(r Reading) ReadingDTO
+}
@@ -0,0 +1,30 @@
+package compose
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompose(t *testing.T) {
+	m := NewTicketMapper()
+	Directory = map[ID]User{1: {ID: 1, Name: "ada"}, 2: {ID: 2, Name: "bob"}}
+
+	t.Run("chains converters and mapper methods", func(t *testing.T) {
+		out, err := m.ToTicket(Ticket{Title: "bug", Owner: "1", Watchers: []string{"2", "1"}}, "urgent")
+		require.NoError(t, err)
+		require.Equal(t, TicketDTO{Title: "bug", Owner: UserDTO{Name: "ada"}, Watchers: []UserDTO{{Name: "bob"}, {Name: "ada"}}, Note: "urgent"}, out)
+	})
+
+	t.Run("errors of any step propagate", func(t *testing.T) {
+		_, err := m.ToTicket(Ticket{Owner: "1", Watchers: []string{"3"}}, "")
+		require.ErrorIs(t, err, ErrUnknownUser)
+		_, err = m.ToTicket(Ticket{Owner: "x"}, "")
+		require.ErrorIs(t, err, strconv.ErrSyntax)
+	})
+
+	t.Run("chains inside helpers", func(t *testing.T) {
+		require.InDelta(t, 212, float64(m.ToReading(Reading{Temp: 100}).Temp), 1e-9)
+	})
+}
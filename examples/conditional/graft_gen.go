@@ -0,0 +1,62 @@
+// Code generated by graftgen (version devel); DO NOT EDIT.
+
+// Source interfaces: ProfileMapper
+// Command: graftgen -interface=ProfileMapper -output=graft_gen.go
+
+package conditional
+
+// map_Profile_to_ProfileDTO maps a value of type Profile to ProfileDTO.
+func map_Profile_to_ProfileDTO(in Profile) (ProfileDTO, error) {
+	var dst ProfileDTO
+	dst.Name = in.Name
+	if in.EmailVerified {
+		dst.Email = in.Email
+	}
+	if IsValidPhone(in.Phone) {
+		dst.Phone = in.Phone
+	}
+	if !in.Deleted {
+		dst.Score = in.Score
+	}
+	if in.Birthday != "" {
+		tmp, err := ParseDate(in.Birthday)
+		if err != nil {
+			return dst, err
+		}
+		dst.Birthday = tmp
+
+	}
+	if in.Anniversary != "" {
+		tmp1, err := ParseDate(in.Anniversary)
+		if err != nil {
+			return dst, err
+		}
+		dst.Anniversary = tmp1
+
+	}
+	return dst, nil
+}
+
+// profileMapperImpl is the generated implementation of ProfileMapper.
+type profileMapperImpl struct{}
+
+// NewProfileMapper returns a new ProfileMapper implementation.
+func NewProfileMapper() ProfileMapper { return &profileMapperImpl{} }
+
+// ToDTO maps p0 to the destination type.
+func (m *profileMapperImpl) ToDTO(p0 Profile) (ProfileDTO, error) {
+	return map_Profile_to_ProfileDTO(p0)
+}
+
+// ToPublic maps p to the destination type.
+func (m *profileMapperImpl) ToPublic(p Profile, opts Options, share bool) PublicProfileDTO {
+	var dst PublicProfileDTO
+	dst.Name = p.Name
+	if opts.Public {
+		dst.Email = p.Email
+	}
+	if share {
+		dst.Phone = p.Phone
+	}
+	return dst
+}
@@ -0,0 +1,56 @@
+package conditional
+
+import (
+	"errors"
+	"time"
+)
+
+//go:generate go run ../../cmd/graftgen -interface=ProfileMapper -output=graft_gen.go
+
+type Profile struct {
+	Name          string
+	Nickname      string
+	Email         string
+	EmailVerified bool
+	Phone         string
+	Deleted       bool
+	Score         int
+	Birthday      string
+	Anniversary   string
+}
+
+type ProfileDTO struct {
+	Name        string
+	Email       string    `mapif:"EmailVerified"`
+	Phone       string    `mapif:"IsValidPhone"`
+	Score       int       `mapif:"!Deleted"`
+	Birthday    time.Time `mapfn:"ParseDate" mapif:"nonzero"`
+	Anniversary time.Time `mapfn:"ParseDate" mapif:"nonzero"`
+}
+
+// IsValidPhone is a predicate over the source field value.
+func IsValidPhone(s string) bool { return len(s) >= 7 }
+
+// ParseDate fails on empty input, so it is only called for non-zero sources.
+func ParseDate(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, errors.New("empty date")
+	}
+	return time.Parse(time.DateOnly, s)
+}
+
+type Options struct {
+	Public bool
+}
+
+// PublicProfileDTO is only filled with contact data when the options allow it.
+type PublicProfileDTO struct {
+	Name  string
+	Email string `mapif:"opts.Public"`
+	Phone string `mapif:"share"`
+}
+
+type ProfileMapper interface {
+	ToDTO(Profile) (ProfileDTO, error)
+	ToPublic(p Profile, opts Options, share bool) PublicProfileDTO
+}
@@ -0,0 +1,48 @@
+package conditional
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConditional(t *testing.T) {
+	m := NewProfileMapper()
+
+	t.Run("conditions hold", func(t *testing.T) {
+		out, err := m.ToDTO(Profile{Email: "a@b.c", EmailVerified: true, Phone: "5551234", Score: 7, Birthday: "2000-01-02", Anniversary: "2020-03-04"})
+		require.NoError(t, err)
+		require.Equal(t, "a@b.c", out.Email)
+		require.Equal(t, "5551234", out.Phone)
+		require.Equal(t, 7, out.Score)
+		require.Equal(t, 2000, out.Birthday.Year())
+		require.Equal(t, 2020, out.Anniversary.Year())
+	})
+
+	t.Run("failing conditions keep zero values", func(t *testing.T) {
+		out, err := m.ToDTO(Profile{Name: "a", Email: "a@b.c", Phone: "12", Deleted: true, Score: 7})
+		require.NoError(t, err) // ParseDate is skipped for empty dates
+		require.Equal(t, "a", out.Name)
+		require.Empty(t, out.Email)
+		require.Empty(t, out.Phone)
+		require.Zero(t, out.Score)
+		require.True(t, out.Birthday.IsZero())
+	})
+
+	t.Run("errors from conditional fields propagate", func(t *testing.T) {
+		_, err := m.ToDTO(Profile{Birthday: "not a date"})
+		require.Error(t, err)
+	})
+
+	t.Run("conditions on method parameters", func(t *testing.T) {
+		p := Profile{Name: "a", Email: "a@b.c", Phone: "5551234"}
+		out := m.ToPublic(p, Options{}, false)
+		require.Equal(t, "a", out.Name)
+		require.Empty(t, out.Email)
+		require.Empty(t, out.Phone)
+
+		out = m.ToPublic(p, Options{Public: true}, true)
+		require.Equal(t, "a@b.c", out.Email)
+		require.Equal(t, "5551234", out.Phone)
+	})
+}
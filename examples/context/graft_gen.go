@@ -5,7 +5,60 @@
 
 package ctxex
 
-import "context"
+import (
+	"context"
+	"strings"
+)
+
+// map_In_to_Out maps a value of type In to Out.
+func map_In_to_Out(in In) Out {
+	var dst Out
+	dst.V = in.V
+	return dst
+}
+
+// map_Cart_to_CartDTO maps a value of type Cart to CartDTO.
+func map_Cart_to_CartDTO(ctx context.Context, in Cart) (CartDTO, error) {
+	var dst CartDTO
+	if in.Items != nil {
+		dst.Items = make([]ItemDTO, len(in.Items))
+		for i, v := range in.Items { // v used by child nodes
+			var mapped ItemDTO
+			tmp, err := map_Item_to_ItemDTO(ctx, v)
+			if err != nil {
+				return dst, err
+			}
+			mapped = tmp
+
+			dst.Items[i] = mapped
+		}
+	} else {
+		dst.Items = nil
+	}
+	return dst, nil
+}
+
+// map_Item_to_ItemView maps a value of type Item to ItemView.
+func map_Item_to_ItemView(ctx context.Context, in Item) ItemView {
+	var dst ItemView
+	dst.Name = in.Name
+	dst.Price = FormatPrice(ctx, in.Price)
+
+	return dst
+}
+
+// map_Item_to_ItemDTO maps a value of type Item to ItemDTO.
+func map_Item_to_ItemDTO(ctx context.Context, in Item) (ItemDTO, error) {
+	var dst ItemDTO
+	tmp, err := ScopeLabel(ctx, strings.TrimSpace(in.Name))
+	if err != nil {
+		return dst, err
+	}
+	dst.Name = tmp
+	dst.Price = FormatPrice(ctx, in.Price)
+
+	return dst, nil
+}
 
 // ctxMapperImpl is the generated implementation of CtxMapper.
 type ctxMapperImpl struct{}
@@ -13,16 +66,32 @@ type ctxMapperImpl struct{}
 // NewCtxMapper returns a new CtxMapper implementation.
 func NewCtxMapper() CtxMapper { return &ctxMapperImpl{} }
 
+// Line maps it to the destination type.
+func (m *ctxMapperImpl) Line(c context.Context, it Item, qty int) LineView {
+	var dst LineView
+	dst.Name = it.Name
+	dst.Price = FormatPrice(c, it.Price)
+
+	dst.Qty = qty
+	return dst
+}
+
 // Map maps p0 to the destination type.
 func (m *ctxMapperImpl) Map(ctx context.Context, p0 In) Out {
-	var dst Out
-	dst.V = p0.V
-	return dst
+	return map_In_to_Out(p0)
+}
+
+// MapCart maps c to the destination type.
+func (m *ctxMapperImpl) MapCart(ctx context.Context, c Cart) (CartDTO, error) {
+	return map_Cart_to_CartDTO(ctx, c)
 }
 
 // MapNamedCtx maps in to the destination type.
 func (m *ctxMapperImpl) MapNamedCtx(c context.Context, in In) Out {
-	var dst Out
-	dst.V = in.V
-	return dst
+	return map_In_to_Out(in)
+}
+
+// View maps it to the destination type.
+func (m *ctxMapperImpl) View(it Item) ItemView {
+	return map_Item_to_ItemView(context.Background(), it)
 }
@@ -1,6 +1,11 @@
 package ctxex
 
-import "context"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
 
 //go:generate go run ../../cmd/graftgen -interface=CtxMapper -output=graft_gen.go
 
@@ -12,7 +17,81 @@ type Out struct {
 	V int
 }
 
+type (
+	localeKey struct{}
+	tenantKey struct{}
+)
+
+// WithLocale returns a context carrying the locale used by FormatPrice.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// WithTenant returns a context carrying the tenant used by ScopeLabel.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+var ErrNoTenant = errors.New("no tenant in context")
+
+type Price struct {
+	Cents int
+}
+
+type Label string
+
+// FormatPrice renders a price with the decimal separator of the context
+// locale.
+func FormatPrice(ctx context.Context, p Price) string {
+	sep := "."
+	if ctx.Value(localeKey{}) == "de" {
+		sep = ","
+	}
+	return fmt.Sprintf("%d%s%02d", p.Cents/100, sep, p.Cents%100)
+}
+
+// ScopeLabel prefixes a label with the tenant of the context.
+func ScopeLabel(ctx context.Context, s string) (Label, error) {
+	tenant, ok := ctx.Value(tenantKey{}).(string)
+	if !ok {
+		return "", ErrNoTenant
+	}
+	return Label(tenant + "/" + strings.ToLower(s)), nil
+}
+
+type Item struct {
+	Name  string
+	Price Price
+}
+
+type ItemDTO struct {
+	Name  Label `mapfn:"strings.TrimSpace,ScopeLabel"`
+	Price string
+}
+
+type ItemView struct {
+	Name  string
+	Price string
+}
+
+type LineView struct {
+	Name  string
+	Price string
+	Qty   int
+}
+
+type Cart struct {
+	Items []Item
+}
+
+type CartDTO struct {
+	Items []ItemDTO
+}
+
 type CtxMapper interface {
 	Map(context.Context, In) Out
 	MapNamedCtx(c context.Context, in In) Out // if context is given a different name, use that
+	MapCart(ctx context.Context, c Cart) (CartDTO, error)
+	View(it Item) ItemView // converters receive context.Background()
+	Line(c context.Context, it Item, qty int) LineView
 }
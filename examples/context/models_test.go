@@ -8,9 +8,32 @@ import (
 )
 
 func TestContext(t *testing.T) {
+	m := NewCtxMapper()
+
 	t.Run("context parameter passes through mapping", func(t *testing.T) {
-		m := NewCtxMapper()
 		out := m.Map(context.Background(), In{V: 42})
 		require.Equal(t, 42, out.V)
 	})
+
+	t.Run("context reaches converters through helpers", func(t *testing.T) {
+		ctx := WithTenant(WithLocale(context.Background(), "de"), "acme")
+		out, err := m.MapCart(ctx, Cart{Items: []Item{{Name: " Shoe ", Price: Price{Cents: 1999}}}})
+		require.NoError(t, err)
+		require.Equal(t, []ItemDTO{{Name: "acme/shoe", Price: "19,99"}}, out.Items)
+	})
+
+	t.Run("context-aware converter errors propagate", func(t *testing.T) {
+		_, err := m.MapCart(context.Background(), Cart{Items: []Item{{Name: "x"}}})
+		require.ErrorIs(t, err, ErrNoTenant)
+	})
+
+	t.Run("named context parameter in method bodies", func(t *testing.T) {
+		out := m.Line(WithLocale(context.Background(), "de"), Item{Name: "a", Price: Price{Cents: 250}}, 3)
+		require.Equal(t, LineView{Name: "a", Price: "2,50", Qty: 3}, out)
+	})
+
+	t.Run("methods without context use a background context", func(t *testing.T) {
+		out := m.View(Item{Name: "a", Price: Price{Cents: 5}})
+		require.Equal(t, "0.05", out.Price)
+	})
 }
@@ -0,0 +1,38 @@
+// Package convert holds conversions shared by several mapper packages.
+package convert
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Money is an amount in minor units.
+type Money struct {
+	Cents    int64
+	Currency string
+}
+
+// SKU is a validated stock keeping unit.
+type SKU string
+
+var ErrInvalidSKU = errors.New("invalid sku")
+
+// MoneyToString formats m as "12.34 EUR".
+func MoneyToString(m Money) string {
+	return fmt.Sprintf("%d.%02d %s", m.Cents/100, m.Cents%100, m.Currency)
+}
+
+// TimeToUnix converts t to seconds since the epoch.
+func TimeToUnix(t time.Time) int64 {
+	return t.Unix()
+}
+
+// ParseSKU validates s as a SKU ("ABC-123").
+func ParseSKU(s string) (SKU, error) {
+	if !strings.Contains(s, "-") {
+		return "", ErrInvalidSKU
+	}
+	return SKU(strings.ToUpper(s)), nil
+}
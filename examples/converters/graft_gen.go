@@ -0,0 +1,64 @@
+// Code generated by graftgen (version devel); DO NOT EDIT.
+
+// Source interfaces: OrderMapper
+// Command: graftgen -interface=OrderMapper -output=graft_gen.go -convert_pkgs=github.com/calumari/graft/examples/converters/convert,github.com/calumari/graft/examples/converters/units/convert
+
+package converters
+
+import (
+	"github.com/calumari/graft/examples/converters/convert"
+	convert2 "github.com/calumari/graft/examples/converters/units/convert"
+)
+
+// map_Order_to_OrderDTO maps a value of type Order to OrderDTO.
+func map_Order_to_OrderDTO(in Order) (OrderDTO, error) {
+	var dst OrderDTO
+	dst.ID = in.ID
+	dst.Total = convert.MoneyToString(in.Total)
+
+	dst.PlacedAt = convert.TimeToUnix(in.PlacedAt)
+
+	if in.Lines != nil {
+		dst.Lines = make([]LineDTO, len(in.Lines))
+		for i, v := range in.Lines { // v used by child nodes
+			var mapped LineDTO
+			tmp, err := map_Line_to_LineDTO(v)
+			if err != nil {
+				return dst, err
+			}
+			mapped = tmp
+
+			dst.Lines[i] = mapped
+		}
+	} else {
+		dst.Lines = nil
+	}
+	return dst, nil
+}
+
+// map_Line_to_LineDTO maps a value of type Line to LineDTO.
+func map_Line_to_LineDTO(in Line) (LineDTO, error) {
+	var dst LineDTO
+	tmp, err := convert.ParseSKU(in.SKU)
+	if err != nil {
+		return dst, err
+	}
+	dst.SKU = tmp
+
+	dst.Price = convert.MoneyToString(in.Price)
+
+	dst.Weight = convert2.GramsToString(in.Weight)
+
+	return dst, nil
+}
+
+// orderMapperImpl is the generated implementation of OrderMapper.
+type orderMapperImpl struct{}
+
+// NewOrderMapper returns a new OrderMapper implementation.
+func NewOrderMapper() OrderMapper { return &orderMapperImpl{} }
+
+// ToDTO maps o to the destination type.
+func (m *orderMapperImpl) ToDTO(o Order) (OrderDTO, error) {
+	return map_Order_to_OrderDTO(o)
+}
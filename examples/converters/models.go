@@ -0,0 +1,40 @@
+package converters
+
+import (
+	"time"
+
+	"github.com/calumari/graft/examples/converters/convert"
+	units "github.com/calumari/graft/examples/converters/units/convert"
+)
+
+//go:generate go run ../../cmd/graftgen -interface=OrderMapper -output=graft_gen.go -convert_pkgs=github.com/calumari/graft/examples/converters/convert,github.com/calumari/graft/examples/converters/units/convert
+
+type Line struct {
+	SKU    string
+	Price  convert.Money
+	Weight units.Grams
+}
+
+type Order struct {
+	ID       int
+	Total    convert.Money
+	PlacedAt time.Time
+	Lines    []Line
+}
+
+type LineDTO struct {
+	SKU    convert.SKU
+	Price  string
+	Weight string
+}
+
+type OrderDTO struct {
+	ID       int
+	Total    string
+	PlacedAt int64
+	Lines    []LineDTO
+}
+
+type OrderMapper interface {
+	ToDTO(o Order) (OrderDTO, error)
+}
@@ -0,0 +1,33 @@
+package converters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/calumari/graft/examples/converters/convert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConverters(t *testing.T) {
+	m := NewOrderMapper()
+	placed := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("converters from an imported package", func(t *testing.T) {
+		out, err := m.ToDTO(Order{
+			ID:       7,
+			Total:    convert.Money{Cents: 1234, Currency: "EUR"},
+			PlacedAt: placed,
+			Lines:    []Line{{SKU: "abc-1", Price: convert.Money{Cents: 500, Currency: "EUR"}, Weight: 1500}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 7, out.ID)
+		require.Equal(t, "12.34 EUR", out.Total)
+		require.Equal(t, placed.Unix(), out.PlacedAt)
+		require.Equal(t, []LineDTO{{SKU: "ABC-1", Price: "5.00 EUR", Weight: "1.50 kg"}}, out.Lines)
+	})
+
+	t.Run("converter errors propagate", func(t *testing.T) {
+		_, err := m.ToDTO(Order{Lines: []Line{{SKU: "bad"}}})
+		require.ErrorIs(t, err, convert.ErrInvalidSKU)
+	})
+}
@@ -0,0 +1,13 @@
+// Package convert holds unit conversions; its name clashes with the shared
+// converters package on purpose.
+package convert
+
+import "fmt"
+
+// Grams is a weight in grams.
+type Grams int
+
+// GramsToString formats g in kilograms ("1.50 kg").
+func GramsToString(g Grams) string {
+	return fmt.Sprintf("%.2f kg", float64(g)/1000)
+}
@@ -0,0 +1,77 @@
+// Code generated by graftgen (version devel); DO NOT EDIT.
+
+// Source interfaces: AccountMapper
+// Command: graftgen -interface=AccountMapper -output=graft_gen.go
+
+package defaults
+
+import "github.com/calumari/graft/examples/defaults/tiers"
+
+// map_Account_to_AccountDTO maps a value of type Account to AccountDTO.
+func map_Account_to_AccountDTO(in Account) AccountDTO {
+	var dst AccountDTO
+	dst.Name = in.Name
+	if in.Status != "" {
+		dst.Status = in.Status
+	} else {
+		dst.Status = StatusActive
+	}
+	if in.Plan != nil {
+		dst.Plan = *in.Plan
+	} else {
+		dst.Plan = "free"
+	}
+	if in.Limit != 0 {
+		dst.Limit = in.Limit
+	} else {
+		dst.Limit = DefaultLimit
+	}
+	dst.Ratio = 0.5
+	dst.Enabled = true
+	if in.Tier != "" {
+		dst.Tier = in.Tier
+	} else {
+		dst.Tier = tiers.Basic
+	}
+	return dst
+}
+
+// accountMapperImpl is the generated implementation of AccountMapper.
+type accountMapperImpl struct{}
+
+// NewAccountMapper returns a new AccountMapper implementation.
+func NewAccountMapper() AccountMapper { return &accountMapperImpl{} }
+
+// ApplyPatch maps p to the destination type.
+func (m *accountMapperImpl) ApplyPatch(p Patch, a Account) AccountDTO {
+	var dst AccountDTO
+	dst.Name = a.Name
+	if a.Status != "" {
+		dst.Status = a.Status
+	} else {
+		dst.Status = StatusActive
+	}
+	if a.Plan != nil {
+		dst.Plan = *a.Plan
+	} else {
+		dst.Plan = "free"
+	}
+	if p.Limit != 0 {
+		dst.Limit = p.Limit
+	} else {
+		dst.Limit = DefaultLimit
+	}
+	dst.Ratio = 0.5
+	dst.Enabled = true
+	if a.Tier != "" {
+		dst.Tier = a.Tier
+	} else {
+		dst.Tier = tiers.Basic
+	}
+	return dst
+}
+
+// ToDTO maps p0 to the destination type.
+func (m *accountMapperImpl) ToDTO(p0 Account) AccountDTO {
+	return map_Account_to_AccountDTO(p0)
+}
@@ -0,0 +1,38 @@
+package defaults
+
+import "github.com/calumari/graft/examples/defaults/tiers"
+
+//go:generate go run ../../cmd/graftgen -interface=AccountMapper -output=graft_gen.go
+
+type Status string
+
+const StatusActive Status = "active"
+
+const DefaultLimit = 10
+
+type Account struct {
+	Name   string
+	Status Status
+	Plan   *string
+	Limit  int
+	Tier   tiers.Level
+}
+
+type AccountDTO struct {
+	Name    string
+	Status  Status      `mapdefault:"StatusActive"`
+	Plan    string      `mapdefault:"\"free\""`
+	Limit   int         `mapdefault:"DefaultLimit"`
+	Ratio   float64     `mapdefault:"0.5"` // absent on the source
+	Enabled bool        `mapdefault:"true"`
+	Tier    tiers.Level `mapdefault:"tiers.Basic"`
+}
+
+type Patch struct {
+	Limit int
+}
+
+type AccountMapper interface {
+	ToDTO(Account) AccountDTO
+	ApplyPatch(p Patch, a Account) AccountDTO
+}
@@ -0,0 +1,38 @@
+package defaults
+
+import (
+	"testing"
+
+	"github.com/calumari/graft/examples/defaults/tiers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaults(t *testing.T) {
+	m := NewAccountMapper()
+
+	t.Run("zero, nil and absent sources use defaults", func(t *testing.T) {
+		out := m.ToDTO(Account{Name: "a"})
+		require.Equal(t, "a", out.Name)
+		require.Equal(t, StatusActive, out.Status)
+		require.Equal(t, "free", out.Plan)
+		require.Equal(t, DefaultLimit, out.Limit)
+		require.InDelta(t, 0.5, out.Ratio, 0)
+		require.True(t, out.Enabled)
+		require.Equal(t, tiers.Basic, out.Tier)
+	})
+
+	t.Run("present sources override defaults", func(t *testing.T) {
+		plan := "pro"
+		out := m.ToDTO(Account{Status: "suspended", Plan: &plan, Limit: 3, Tier: "gold"})
+		require.Equal(t, Status("suspended"), out.Status)
+		require.Equal(t, "pro", out.Plan)
+		require.Equal(t, 3, out.Limit)
+		require.Equal(t, tiers.Level("gold"), out.Tier)
+	})
+
+	t.Run("defaults apply to multi-param methods", func(t *testing.T) {
+		out := m.ApplyPatch(Patch{}, Account{Name: "a"})
+		require.Equal(t, DefaultLimit, out.Limit)
+		require.Equal(t, "free", out.Plan)
+	})
+}
@@ -0,0 +1,8 @@
+// Package tiers holds the account tiers shared with billing.
+package tiers
+
+// Level is an account tier.
+type Level string
+
+// Basic is the tier of accounts without a subscription.
+const Basic Level = "basic"
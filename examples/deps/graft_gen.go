@@ -0,0 +1,81 @@
+// Code generated by graftgen (version devel); DO NOT EDIT.
+
+// Source interfaces: InvoiceMapper
+// Command: graftgen -interface=InvoiceMapper -output=graft_gen.go
+
+package deps
+
+import "strings"
+
+// map_Invoice_to_InvoiceDTO maps a value of type Invoice to InvoiceDTO.
+func map_Invoice_to_InvoiceDTO(deps *Services, in Invoice) (InvoiceDTO, error) {
+	var dst InvoiceDTO
+	dst.ID = deps.PublicID(in.ID)
+
+	if in.Lines != nil {
+		dst.Lines = make([]LineDTO, len(in.Lines))
+		for i, v := range in.Lines { // v used by child nodes
+			var mapped LineDTO
+			tmp, err := map_Line_to_LineDTO(deps, v)
+			if err != nil {
+				return dst, err
+			}
+			mapped = tmp
+
+			dst.Lines[i] = mapped
+		}
+	} else {
+		dst.Lines = nil
+	}
+	tmp1, err := deps.ToUSD(in.Total)
+	if err != nil {
+		return dst, err
+	}
+	dst.Total = tmp1
+
+	dst.Note = deps.Sign(strings.TrimSpace(in.Note))
+	return dst, nil
+}
+
+// map_Line_to_LineDTO maps a value of type Line to LineDTO.
+func map_Line_to_LineDTO(deps *Services, in Line) (LineDTO, error) {
+	var dst LineDTO
+	dst.ID = deps.PublicID(in.ID)
+
+	tmp, err := deps.ToUSD(in.Price)
+	if err != nil {
+		return dst, err
+	}
+	dst.Price = tmp
+
+	return dst, nil
+}
+
+// invoiceMapperImpl is the generated implementation of InvoiceMapper.
+type invoiceMapperImpl struct {
+	deps *Services
+}
+
+// NewInvoiceMapper returns a new InvoiceMapper implementation using deps for
+// stateful conversions.
+func NewInvoiceMapper(deps *Services) InvoiceMapper { return &invoiceMapperImpl{deps: deps} }
+
+// ToDTO maps inv to the destination type.
+func (m *invoiceMapperImpl) ToDTO(inv Invoice) (InvoiceDTO, error) {
+	return map_Invoice_to_InvoiceDTO(m.deps, inv)
+}
+
+// ToSummary maps inv to the destination type.
+func (m *invoiceMapperImpl) ToSummary(inv Invoice, author string) (Summary, error) {
+	var dst Summary
+	dst.ID = m.deps.PublicID(inv.ID)
+
+	tmp, err := m.deps.ToUSD(inv.Total)
+	if err != nil {
+		return dst, err
+	}
+	dst.Total = tmp
+
+	dst.Author = author
+	return dst, nil
+}
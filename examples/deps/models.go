@@ -0,0 +1,80 @@
+package deps
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+//go:generate go run ../../cmd/graftgen -interface=InvoiceMapper -output=graft_gen.go
+
+var ErrUnknownCurrency = errors.New("unknown currency")
+
+// Services holds the state needed by conversions: exchange rates and the
+// salt used to obfuscate public IDs.
+type Services struct {
+	Rates map[string]float64 // USD per unit of currency
+	Salt  int64
+}
+
+// ToUSD converts m using the configured exchange rates.
+func (s *Services) ToUSD(m Money) (USD, error) {
+	rate, ok := s.Rates[m.Currency]
+	if !ok {
+		return 0, ErrUnknownCurrency
+	}
+	return USD(m.Amount * rate), nil
+}
+
+// PublicID obfuscates an internal ID.
+func (s *Services) PublicID(id int64) string {
+	return strings.ToUpper(strconv.FormatInt(id^s.Salt, 36))
+}
+
+// Sign marks a note with the obfuscation salt.
+func (s *Services) Sign(note string) string {
+	return note + " #" + strconv.FormatInt(s.Salt, 10)
+}
+
+type USD float64
+
+type Money struct {
+	Amount   float64
+	Currency string
+}
+
+type Line struct {
+	ID    int64
+	Price Money
+}
+
+type LineDTO struct {
+	ID    string
+	Price USD
+}
+
+type Invoice struct {
+	ID    int64
+	Lines []Line
+	Total Money
+	Note  string
+}
+
+type InvoiceDTO struct {
+	ID    string
+	Lines []LineDTO
+	Total USD
+	Note  string `mapfn:"strings.TrimSpace,Sign"`
+}
+
+type Summary struct {
+	ID     string
+	Total  USD
+	Author string
+}
+
+//graft:deps *Services
+type InvoiceMapper interface {
+	ToDTO(inv Invoice) (InvoiceDTO, error)
+	ToSummary(inv Invoice, author string) (Summary, error)
+}
@@ -0,0 +1,37 @@
+package deps
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeps(t *testing.T) {
+	svc := &Services{Rates: map[string]float64{"EUR": 2, "USD": 1}, Salt: 7}
+	m := NewInvoiceMapper(svc)
+
+	t.Run("dependency methods convert fields", func(t *testing.T) {
+		out, err := m.ToDTO(Invoice{
+			ID:    100,
+			Lines: []Line{{ID: 1, Price: Money{Amount: 5, Currency: "EUR"}}},
+			Total: Money{Amount: 10, Currency: "EUR"},
+			Note:  " thanks ",
+		})
+		require.NoError(t, err)
+		require.Equal(t, svc.PublicID(100), out.ID)
+		require.Equal(t, []LineDTO{{ID: svc.PublicID(1), Price: 10}}, out.Lines)
+		require.Equal(t, USD(20), out.Total)
+		require.Equal(t, "thanks #7", out.Note)
+	})
+
+	t.Run("dependency errors propagate", func(t *testing.T) {
+		_, err := m.ToDTO(Invoice{Lines: []Line{{Price: Money{Currency: "XYZ"}}}})
+		require.ErrorIs(t, err, ErrUnknownCurrency)
+	})
+
+	t.Run("dependency in method bodies", func(t *testing.T) {
+		out, err := m.ToSummary(Invoice{ID: 3, Total: Money{Amount: 4, Currency: "USD"}}, "ann")
+		require.NoError(t, err)
+		require.Equal(t, Summary{ID: svc.PublicID(3), Total: 4, Author: "ann"}, out)
+	})
+}
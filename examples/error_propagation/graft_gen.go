@@ -23,6 +23,30 @@ func map_Input_to_Output(in Input) (Output, error) {
 	} else {
 		dst.Items = nil
 	}
+	if in.In != nil {
+		tmp1, err := map_Ptr_Inner_to_Ptr_InnerDTO(in.In)
+		if err != nil {
+			return dst, err
+		}
+		dst.In = tmp1
+	} else {
+		dst.In = nil
+	}
+	return dst, nil
+}
+
+// map_Ptr_Inner_to_Ptr_InnerDTO maps a value of type *Inner to *InnerDTO.
+func map_Ptr_Inner_to_Ptr_InnerDTO(in *Inner) (*InnerDTO, error) {
+	if in == nil {
+		return nil, nil
+	}
+	dst := new(InnerDTO)
+	tmp, err := ParseCode(in.Code)
+	if err != nil {
+		return dst, err
+	}
+	dst.Code = tmp
+
 	return dst, nil
 }
 
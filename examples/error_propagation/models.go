@@ -1,6 +1,9 @@
 package error_propagation
 
-import "fmt"
+import (
+	"fmt"
+	"strconv"
+)
 
 //go:generate go run ../../cmd/graftgen -interface=Mapper -output=graft_gen.go
 
@@ -12,14 +15,28 @@ type ItemDTO struct {
 	V int
 }
 
+// Inner is reached through a pointer whose helper can fail.
+type Inner struct {
+	Code string
+}
+
+type InnerDTO struct {
+	Code int `mapfn:"ParseCode"`
+}
+
 type Input struct {
 	Items []Item
+	In    *Inner
 }
 
 type Output struct {
 	Items []ItemDTO `mapfn:"ItemToDTO"`
+	In    *InnerDTO
 }
 
+// ParseCode parses a numeric code.
+func ParseCode(s string) (int, error) { return strconv.Atoi(s) }
+
 // Custom function returns error for negative values.
 func ItemToDTO(i Item) (ItemDTO, error) {
 	if i.V < 0 {
@@ -1,6 +1,7 @@
 package error_propagation
 
 import (
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -20,4 +21,17 @@ func TestErrorPropagation(t *testing.T) {
 		require.Len(t, out.Items, 1)
 		require.Equal(t, 5, out.Items[0].V)
 	})
+
+	t.Run("nested pointer helpers propagate errors", func(t *testing.T) {
+		out, err := m.Map(Input{In: &Inner{Code: "7"}})
+		require.NoError(t, err)
+		require.Equal(t, &InnerDTO{Code: 7}, out.In)
+
+		out, err = m.Map(Input{})
+		require.NoError(t, err)
+		require.Nil(t, out.In)
+
+		_, err = m.Map(Input{In: &Inner{Code: "x"}})
+		require.ErrorIs(t, err, strconv.ErrSyntax)
+	})
 }
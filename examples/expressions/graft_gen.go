@@ -0,0 +1,56 @@
+// Code generated by graftgen (version devel); DO NOT EDIT.
+
+// Source interfaces: CardMapper, UserMapper
+// Command: graftgen -interface=UserMapper,CardMapper -output=graft_gen.go
+
+package expressions
+
+import "strings"
+
+// map_Ptr_User_to_CardDTO maps a value of type *User to CardDTO.
+func map_Ptr_User_to_CardDTO(in *User) CardDTO {
+	if in == nil {
+		return CardDTO{}
+	}
+	var dst CardDTO
+	dst.Initials = initial(in.First) + initial(in.Last)
+	dst.Domain = in.Email[strings.LastIndex(in.Email, "@")+1:]
+	return dst
+}
+
+// cardMapperImpl is the generated implementation of CardMapper.
+type cardMapperImpl struct{}
+
+// NewCardMapper returns a new CardMapper implementation.
+func NewCardMapper() CardMapper { return &cardMapperImpl{} }
+
+// ToCard maps p0 to the destination type.
+func (m *cardMapperImpl) ToCard(p0 *User) CardDTO {
+	return map_Ptr_User_to_CardDTO(p0)
+}
+
+// userMapperImpl is the generated implementation of UserMapper.
+type userMapperImpl struct{}
+
+// NewUserMapper returns a new UserMapper implementation.
+func NewUserMapper() UserMapper { return &userMapperImpl{} }
+
+// ToDTO maps p0 to the destination type.
+func (m *userMapperImpl) ToDTO(p0 User) UserDTO {
+	var dst UserDTO
+	dst.Kind = "user"
+	dst.Version = CurrentAPIVersion
+	dst.FullName = p0.First + " " + p0.Last
+	dst.Email = strings.ToLower(p0.Email)
+	return dst
+}
+
+// WithContact maps u to the destination type.
+func (m *userMapperImpl) WithContact(u User, c Contact) UserDTO {
+	var dst UserDTO
+	dst.Kind = "contact"
+	// no source for Version
+	dst.FullName = strings.ToUpper(u.Last)
+	dst.Email = c.Email
+	return dst
+}
@@ -0,0 +1,53 @@
+package expressions
+
+import "strings"
+
+//go:generate go run ../../cmd/graftgen -interface=UserMapper,CardMapper -output=graft_gen.go
+
+const CurrentAPIVersion = 3
+
+type User struct {
+	First string
+	Last  string
+	Email string
+}
+
+type UserDTO struct {
+	Kind     string
+	Version  int
+	FullName string `mapexpr:"in.First + \" \" + in.Last"`
+	Email    string `mapexpr:"strings.ToLower(in.Email)"`
+}
+
+type Contact struct {
+	Email string
+}
+
+type UserMapper interface {
+	//graft:expr Kind "user"
+	//graft:expr Version CurrentAPIVersion
+	ToDTO(User) UserDTO
+
+	// Tag expressions on multi-parameter methods refer to the parameter names.
+	//graft:expr FullName strings.ToUpper(u.Last)
+	//graft:expr Email c.Email
+	//graft:expr Kind "contact"
+	WithContact(u User, c Contact) UserDTO
+}
+
+// CardDTO is mapped by a shared helper, where "in" is the source value.
+type CardDTO struct {
+	Initials string `mapexpr:"initial(in.First) + initial(in.Last)"`
+	Domain   string `mapexpr:"in.Email[strings.LastIndex(in.Email, \"@\")+1:]"`
+}
+
+type CardMapper interface {
+	ToCard(*User) CardDTO
+}
+
+func initial(s string) string {
+	if s == "" {
+		return ""
+	}
+	return strings.ToUpper(s[:1])
+}
@@ -0,0 +1,33 @@
+package expressions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpressions(t *testing.T) {
+	m := NewUserMapper()
+
+	t.Run("constants and tag expressions fill fields", func(t *testing.T) {
+		out := m.ToDTO(User{First: "Ada", Last: "Lovelace", Email: "ADA@Example.com"})
+		require.Equal(t, "user", out.Kind)
+		require.Equal(t, CurrentAPIVersion, out.Version)
+		require.Equal(t, "Ada Lovelace", out.FullName)
+		require.Equal(t, "ada@example.com", out.Email)
+	})
+
+	t.Run("method directives override tags per method", func(t *testing.T) {
+		out := m.WithContact(User{Last: "Lovelace"}, Contact{Email: "a@b.c"})
+		require.Equal(t, "contact", out.Kind)
+		require.Equal(t, "LOVELACE", out.FullName)
+		require.Equal(t, "a@b.c", out.Email)
+		require.Zero(t, out.Version)
+	})
+
+	t.Run("tag expressions in helpers see the source as in", func(t *testing.T) {
+		out := NewCardMapper().ToCard(&User{First: "ada", Last: "lovelace", Email: "ada@example.com"})
+		require.Equal(t, "AL", out.Initials)
+		require.Equal(t, "example.com", out.Domain)
+	})
+}
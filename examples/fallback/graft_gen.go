@@ -0,0 +1,49 @@
+// Code generated by graftgen (version devel); DO NOT EDIT.
+
+// Source interfaces: Merger
+// Command: graftgen -interface=Merger -output=graft_gen.go
+
+package fallback
+
+// map_Record_to_Display maps a value of type Record to Display.
+func map_Record_to_Display(in Record) Display {
+	var dst Display
+	if in.Profile != nil && in.Profile.Nickname != "" {
+		dst.Label = in.Profile.Nickname
+	} else {
+		dst.Label = in.Name
+	}
+	return dst
+}
+
+// mergerImpl is the generated implementation of Merger.
+type mergerImpl struct{}
+
+// NewMerger returns a new Merger implementation.
+func NewMerger() Merger { return &mergerImpl{} }
+
+// Display maps p0 to the destination type.
+func (m *mergerImpl) Display(p0 Record) Display {
+	return map_Record_to_Display(p0)
+}
+
+// Merge maps req to the destination type.
+func (m *mergerImpl) Merge(req Request, existing Record) User {
+	var dst User
+	if req.Name != "" {
+		dst.Name = req.Name
+	} else {
+		dst.Name = existing.Name
+	}
+	if req.Email != nil {
+		dst.Email = *req.Email
+	} else {
+		dst.Email = existing.Email
+	}
+	if len(req.Tags) > 0 {
+		dst.Tags = req.Tags
+	} else {
+		dst.Tags = existing.Tags
+	}
+	return dst
+}
@@ -0,0 +1,36 @@
+package fallback
+
+//go:generate go run ../../cmd/graftgen -interface=Merger -output=graft_gen.go
+
+type Request struct {
+	Name  string
+	Email *string
+	Tags  []string
+}
+
+type Record struct {
+	Name    string
+	Email   string
+	Tags    []string
+	Profile *Profile
+}
+
+type Profile struct {
+	Nickname string
+}
+
+type User struct {
+	Name  string   `mapsrc:"req.Name|existing.Name"`
+	Email string   `mapsrc:"req.Email|existing.Email"`
+	Tags  []string `mapsrc:"req.Tags|existing.Tags"`
+}
+
+// Display falls back through a nil-able nested pointer.
+type Display struct {
+	Label string `mapsrc:"Profile.Nickname|Name"`
+}
+
+type Merger interface {
+	Merge(req Request, existing Record) User
+	Display(Record) Display
+}
@@ -0,0 +1,34 @@
+package fallback
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFallback(t *testing.T) {
+	m := NewMerger()
+	existing := Record{Name: "stored", Email: "stored@example.com", Tags: []string{"old"}}
+
+	t.Run("non-zero request fields win", func(t *testing.T) {
+		email := "new@example.com"
+		out := m.Merge(Request{Name: "new", Email: &email, Tags: []string{"a"}}, existing)
+		require.Equal(t, "new", out.Name)
+		require.Equal(t, "new@example.com", out.Email)
+		require.Equal(t, []string{"a"}, out.Tags)
+	})
+
+	t.Run("zero and nil request fields fall back to existing", func(t *testing.T) {
+		out := m.Merge(Request{}, existing)
+		require.Equal(t, "stored", out.Name)
+		require.Equal(t, "stored@example.com", out.Email)
+		require.Equal(t, []string{"old"}, out.Tags)
+	})
+
+	t.Run("nil pointer hop falls back", func(t *testing.T) {
+		require.Equal(t, "stored", m.Display(existing).Label)
+		withProfile := existing
+		withProfile.Profile = &Profile{Nickname: "nick"}
+		require.Equal(t, "nick", m.Display(withProfile).Label)
+	})
+}
@@ -0,0 +1,103 @@
+// Code generated by graftgen (version devel); DO NOT EDIT.
+
+// Source interfaces: OrderMapper
+// Command: graftgen -interface=OrderMapper -output=graft_gen.go
+
+package filters
+
+// map_Account_to_AccountDTO maps a value of type Account to AccountDTO.
+func map_Account_to_AccountDTO(in Account) AccountDTO {
+	var dst AccountDTO
+	if in.Orders != nil {
+		dst.Orders = make([]OrderDTO, 0, len(in.Orders))
+		for _, v := range in.Orders { // v used by child nodes
+			if !(v != nil) {
+				continue
+			}
+			var mapped OrderDTO
+			mapped = map_Ptr_Order_to_OrderDTO(v)
+
+			dst.Orders = append(dst.Orders, mapped)
+		}
+	} else {
+		dst.Orders = nil
+	}
+	if in.Open != nil {
+		dst.Open = make([]OrderDTO, 0, len(in.Open))
+		for _, v := range in.Open { // v used by child nodes
+			if !(v != nil && IsActive(v)) {
+				continue
+			}
+			var mapped OrderDTO
+			mapped = map_Ptr_Order_to_OrderDTO(v)
+
+			dst.Open = append(dst.Open, mapped)
+		}
+	} else {
+		dst.Open = nil
+	}
+	if in.Notes != nil {
+		dst.Notes = make([]string, 0, len(in.Notes))
+		for _, v := range in.Notes { // v used by child nodes
+			if !(v != "") {
+				continue
+			}
+			var mapped string
+			mapped = v
+			dst.Notes = append(dst.Notes, mapped)
+		}
+	} else {
+		dst.Notes = nil
+	}
+	if in.ByID != nil {
+		dst.ByID = make(map[string]OrderDTO, len(in.ByID))
+		for k, v := range in.ByID { // k,v used by child nodes
+			if !(v != nil) {
+				continue
+			}
+			var mapped OrderDTO
+			mapped = map_Ptr_Order_to_OrderDTO(v)
+
+			dst.ByID[k] = mapped
+		}
+	} else {
+		dst.ByID = nil
+	}
+	if in.Archive != nil {
+		dst.Archive = make([]OrderDTO, 0, len(in.Archive))
+		for _, v := range in.Archive { // v used by child nodes
+			if !(v != nil && !IsActive(v)) {
+				continue
+			}
+			var mapped OrderDTO
+			mapped = map_Ptr_Order_to_OrderDTO(v)
+
+			dst.Archive = append(dst.Archive, mapped)
+		}
+	} else {
+		dst.Archive = nil
+	}
+	return dst
+}
+
+// map_Ptr_Order_to_OrderDTO maps a value of type *Order to OrderDTO.
+func map_Ptr_Order_to_OrderDTO(in *Order) OrderDTO {
+	if in == nil {
+		return OrderDTO{}
+	}
+	var dst OrderDTO
+	dst.ID = in.ID
+	dst.Status = in.Status
+	return dst
+}
+
+// orderMapperImpl is the generated implementation of OrderMapper.
+type orderMapperImpl struct{}
+
+// NewOrderMapper returns a new OrderMapper implementation.
+func NewOrderMapper() OrderMapper { return &orderMapperImpl{} }
+
+// ToDTO maps p0 to the destination type.
+func (m *orderMapperImpl) ToDTO(p0 Account) AccountDTO {
+	return map_Account_to_AccountDTO(p0)
+}
@@ -0,0 +1,36 @@
+package filters
+
+//go:generate go run ../../cmd/graftgen -interface=OrderMapper -output=graft_gen.go
+
+type Order struct {
+	ID     string
+	Status string
+}
+
+// IsActive reports whether an order is still open.
+func IsActive(o *Order) bool { return o.Status != "closed" }
+
+type OrderDTO struct {
+	ID     string
+	Status string
+}
+
+type Account struct {
+	Orders  []*Order
+	Open    []*Order
+	Notes   []string
+	ByID    map[string]*Order
+	Archive []*Order
+}
+
+type AccountDTO struct {
+	Orders  []OrderDTO          `mapfilter:"nonnil"`
+	Open    []OrderDTO          `mapfilter:"nonnil,IsActive"`
+	Notes   []string            `mapfilter:"nonzero"`
+	ByID    map[string]OrderDTO `mapfilter:"nonnil"`
+	Archive []OrderDTO          `mapfilter:"nonnil,!IsActive"`
+}
+
+type OrderMapper interface {
+	ToDTO(Account) AccountDTO
+}
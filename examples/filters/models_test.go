@@ -0,0 +1,34 @@
+package filters
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilters(t *testing.T) {
+	m := NewOrderMapper()
+
+	t.Run("skips filtered elements", func(t *testing.T) {
+		open, closed := &Order{ID: "1", Status: "open"}, &Order{ID: "2", Status: "closed"}
+		out := m.ToDTO(Account{
+			Orders:  []*Order{open, nil, closed},
+			Open:    []*Order{nil, open, closed},
+			Notes:   []string{"", "a", ""},
+			ByID:    map[string]*Order{"1": open, "x": nil},
+			Archive: []*Order{open, nil, closed},
+		})
+		require.Equal(t, []OrderDTO{{ID: "1", Status: "open"}, {ID: "2", Status: "closed"}}, out.Orders)
+		require.Equal(t, []OrderDTO{{ID: "1", Status: "open"}}, out.Open)
+		require.Equal(t, []string{"a"}, out.Notes)
+		require.Equal(t, map[string]OrderDTO{"1": {ID: "1", Status: "open"}}, out.ByID)
+		require.Equal(t, []OrderDTO{{ID: "2", Status: "closed"}}, out.Archive)
+	})
+
+	t.Run("keeps nil and empty collections apart", func(t *testing.T) {
+		out := m.ToDTO(Account{Orders: []*Order{nil}})
+		require.NotNil(t, out.Orders)
+		require.Empty(t, out.Orders)
+		require.Nil(t, out.Open)
+	})
+}
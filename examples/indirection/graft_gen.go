@@ -0,0 +1,63 @@
+// Code generated by graftgen (version devel); DO NOT EDIT.
+
+// Source interfaces: OrderMapper
+// Command: graftgen -interface=OrderMapper -output=graft_gen.go
+
+package indirection
+
+// map_User_to_UserDTO maps a value of type User to UserDTO.
+func map_User_to_UserDTO(in User) UserDTO {
+	var dst UserDTO
+	dst.Name = in.Name
+	return dst
+}
+
+// orderMapperImpl is the generated implementation of OrderMapper.
+type orderMapperImpl struct{}
+
+// NewOrderMapper returns a new OrderMapper implementation.
+func NewOrderMapper() OrderMapper { return &orderMapperImpl{} }
+
+// ToOrder maps o to the destination type.
+func (m *orderMapperImpl) ToOrder(o Order, by string) (OrderDTO, error) {
+	var dst OrderDTO
+	tmp, err := PriceToDTO(o.Price)
+	if err != nil {
+		return dst, err
+	}
+	dst.Price = &tmp
+	if o.Discount != nil {
+		tmp1, err := PriceToDTO(*o.Discount)
+		if err != nil {
+			return dst, err
+		}
+		dst.Discount = tmp1
+	}
+	tmp2 := NoteToDTO(&o.Note)
+	if tmp2 != nil {
+		dst.Note = *tmp2
+	}
+	dst.Memo = NoteToDTO(&o.Memo)
+	if o.Tag != nil {
+		tmp3 := TagToLabel(*o.Tag)
+		dst.Tag = &tmp3
+	} else {
+		dst.Tag = nil
+	}
+	if o.Owner != nil {
+		tmp4 := m.ToUser(*o.Owner)
+		dst.Owner = &tmp4
+	} else {
+		dst.Owner = nil
+	}
+	if o.Buyer != nil {
+		dst.Buyer = m.ToUser(*o.Buyer)
+	}
+	dst.By = by
+	return dst, nil
+}
+
+// ToUser maps u to the destination type.
+func (m *orderMapperImpl) ToUser(u User) UserDTO {
+	return map_User_to_UserDTO(u)
+}
@@ -0,0 +1,69 @@
+package indirection
+
+import (
+	"errors"
+	"strings"
+)
+
+//go:generate go run ../../cmd/graftgen -interface=OrderMapper -output=graft_gen.go
+
+var ErrNegativePrice = errors.New("negative price")
+
+type Price struct{ Cents int }
+
+type PriceDTO struct{ Amount float64 }
+
+// PriceToDTO converts a price value.
+func PriceToDTO(p Price) (PriceDTO, error) {
+	if p.Cents < 0 {
+		return PriceDTO{}, ErrNegativePrice
+	}
+	return PriceDTO{Amount: float64(p.Cents) / 100}, nil
+}
+
+type Note struct{ Text string }
+
+type NoteDTO struct{ Text string }
+
+// NoteToDTO converts a note pointer, dropping blank notes.
+func NoteToDTO(n *Note) *NoteDTO {
+	if n == nil || strings.TrimSpace(n.Text) == "" {
+		return nil
+	}
+	return &NoteDTO{Text: strings.TrimSpace(n.Text)}
+}
+
+type Tag string
+type Label string
+
+func TagToLabel(t Tag) Label { return Label(strings.ToUpper(string(t))) }
+
+type User struct{ Name string }
+
+type UserDTO struct{ Name string }
+
+type Order struct {
+	Price    Price
+	Discount *Price
+	Note     Note
+	Memo     Note
+	Tag      *Tag
+	Owner    *User
+	Buyer    *User
+}
+
+type OrderDTO struct {
+	Price    *PriceDTO
+	Discount PriceDTO
+	Note     NoteDTO
+	Memo     *NoteDTO
+	Tag      *Label
+	Owner    *UserDTO
+	Buyer    UserDTO
+	By       string
+}
+
+type OrderMapper interface {
+	ToUser(u User) UserDTO
+	ToOrder(o Order, by string) (OrderDTO, error)
+}
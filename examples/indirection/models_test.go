@@ -0,0 +1,52 @@
+package indirection
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndirection(t *testing.T) {
+	m := NewOrderMapper()
+
+	t.Run("value and pointer converters adapt to field indirection", func(t *testing.T) {
+		tag := Tag("gift")
+		out, err := m.ToOrder(Order{
+			Price:    Price{Cents: 1250},
+			Discount: &Price{Cents: 100},
+			Note:     Note{Text: " fragile "},
+			Memo:     Note{Text: "leave at door"},
+			Tag:      &tag,
+			Owner:    &User{Name: "ada"},
+			Buyer:    &User{Name: "bob"},
+		}, "clerk")
+		require.NoError(t, err)
+		label := Label("GIFT")
+		require.Equal(t, OrderDTO{
+			Price:    &PriceDTO{Amount: 12.5},
+			Discount: PriceDTO{Amount: 1},
+			Note:     NoteDTO{Text: "fragile"},
+			Memo:     &NoteDTO{Text: "leave at door"},
+			Tag:      &label,
+			Owner:    &UserDTO{Name: "ada"},
+			Buyer:    UserDTO{Name: "bob"},
+			By:       "clerk",
+		}, out)
+	})
+
+	t.Run("nil sources and results leave zero values", func(t *testing.T) {
+		out, err := m.ToOrder(Order{Note: Note{Text: " "}}, "")
+		require.NoError(t, err)
+		require.Equal(t, PriceDTO{}, out.Discount)
+		require.Equal(t, NoteDTO{}, out.Note)
+		require.Nil(t, out.Memo)
+		require.Nil(t, out.Tag)
+		require.Nil(t, out.Owner)
+		require.Equal(t, UserDTO{}, out.Buyer)
+	})
+
+	t.Run("adapted converter errors propagate", func(t *testing.T) {
+		_, err := m.ToOrder(Order{Discount: &Price{Cents: -1}}, "")
+		require.ErrorIs(t, err, ErrNegativePrice)
+	})
+}
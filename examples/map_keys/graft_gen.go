@@ -0,0 +1,123 @@
+// Code generated by graftgen (version devel); DO NOT EDIT.
+
+// Source interfaces: TeamMapper
+// Command: graftgen -interface=TeamMapper -output=graft_gen.go
+
+package mapkeys
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+)
+
+// map_Team_to_TeamDTO maps a value of type Team to TeamDTO.
+func map_Team_to_TeamDTO(in Team) (TeamDTO, error) {
+	var dst TeamDTO
+	if in.Members != nil {
+		dst.Members = make(map[string]RoleDTO, len(in.Members))
+		for k, v := range in.Members { // k,v used by child nodes
+			var key string
+			var mapped RoleDTO
+			key = strconv.FormatInt(int64(k), 10)
+			mapped = RoleToDTO(v)
+
+			dst.Members[key] = mapped
+		}
+	} else {
+		dst.Members = nil
+	}
+	if in.Counts != nil {
+		dst.Counts = make(map[string]int, len(in.Counts))
+		for k, v := range in.Counts { // k,v used by child nodes
+			var key string
+			var mapped int
+			key = k.String()
+			mapped = v
+			if _, dup := dst.Counts[key]; dup {
+				return dst, fmt.Errorf("duplicate key %v mapping in.Counts", key)
+			}
+			dst.Counts[key] = mapped
+		}
+	} else {
+		dst.Counts = nil
+	}
+	if in.Ports != nil {
+		dst.Ports = make(map[uint16]bool, len(in.Ports))
+		for k, v := range in.Ports { // k,v used by child nodes
+			var key uint16
+			var mapped bool
+			tmp, err := strconv.ParseUint(k, 10, 16)
+			if err != nil {
+				return dst, err
+			}
+			key = uint16(tmp)
+			mapped = v
+			if _, dup := dst.Ports[key]; dup {
+				return dst, fmt.Errorf("duplicate key %v mapping in.Ports", key)
+			}
+			dst.Ports[key] = mapped
+		}
+	} else {
+		dst.Ports = nil
+	}
+	if in.Aliases != nil {
+		dst.Aliases = make(map[string]UserID, len(in.Aliases))
+		for _, k := range slices.Sorted(maps.Keys(in.Aliases)) {
+			v := in.Aliases[k] // v used by child nodes
+			var key string
+			var mapped UserID
+			key = NormalizeEmail(k)
+
+			mapped = v
+			dst.Aliases[key] = mapped
+		}
+	} else {
+		dst.Aliases = nil
+	}
+	if in.Owners != nil {
+		dst.Owners = make(map[string]UserID, len(in.Owners))
+		for _, k := range slices.Sorted(maps.Keys(in.Owners)) {
+			v := in.Owners[k] // v used by child nodes
+			var key string
+			var mapped UserID
+			key = NormalizeEmail(k)
+
+			mapped = v
+			if _, dup := dst.Owners[key]; !dup {
+				dst.Owners[key] = mapped
+			}
+		}
+	} else {
+		dst.Owners = nil
+	}
+	if in.Inboxes != nil {
+		dst.Inboxes = make(map[string]int, len(in.Inboxes))
+		for k, v := range in.Inboxes { // k,v used by child nodes
+			var key string
+			var mapped int
+			key = NormalizeEmail(k)
+
+			mapped = v
+			if _, dup := dst.Inboxes[key]; dup {
+				return dst, fmt.Errorf("duplicate key %v mapping in.Inboxes", key)
+			}
+			dst.Inboxes[key] = mapped
+		}
+	} else {
+		dst.Inboxes = nil
+	}
+	return dst, nil
+}
+
+// teamMapperImpl is the generated implementation of TeamMapper.
+type teamMapperImpl struct{}
+
+// NewTeamMapper returns a new TeamMapper implementation.
+func NewTeamMapper() TeamMapper { return &teamMapperImpl{} }
+
+// ToDTO maps t to the destination type.
+func (m *teamMapperImpl) ToDTO(t Team) (TeamDTO, error) {
+	return map_Team_to_TeamDTO(t)
+}
@@ -0,0 +1,53 @@
+package mapkeys
+
+import "strings"
+
+//go:generate go run ../../cmd/graftgen -interface=TeamMapper -output=graft_gen.go
+
+type UserID int64
+
+type Role int
+
+const (
+	RoleViewer Role = iota
+	RoleAdmin
+)
+
+func (r Role) String() string {
+	if r == RoleAdmin {
+		return "admin"
+	}
+	return "viewer"
+}
+
+type RoleDTO string
+
+// RoleToDTO maps role values; keys use the Stringer instead.
+func RoleToDTO(r Role) RoleDTO { return RoleDTO(r.String()) }
+
+type Email string
+
+// NormalizeEmail lower-cases an address; distinct inputs may collide.
+func NormalizeEmail(e Email) string { return strings.ToLower(string(e)) }
+
+type Team struct {
+	Members map[UserID]Role
+	Counts  map[Role]int
+	Ports   map[string]bool
+	Aliases map[Email]UserID
+	Owners  map[Email]UserID
+	Inboxes map[Email]int
+}
+
+type TeamDTO struct {
+	Members map[string]RoleDTO
+	Counts  map[string]int
+	Ports   map[uint16]bool
+	Aliases map[string]UserID `mapdup:"last"`
+	Owners  map[string]UserID `mapdup:"first"`
+	Inboxes map[string]int
+}
+
+type TeamMapper interface {
+	ToDTO(t Team) (TeamDTO, error)
+}
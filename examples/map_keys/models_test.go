@@ -0,0 +1,42 @@
+package mapkeys
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMapKeys(t *testing.T) {
+	m := NewTeamMapper()
+
+	t.Run("keys convert like values", func(t *testing.T) {
+		out, err := m.ToDTO(Team{
+			Members: map[UserID]Role{7: RoleAdmin, 9: RoleViewer},
+			Counts:  map[Role]int{RoleAdmin: 1},
+			Ports:   map[string]bool{"8080": true},
+			Aliases: map[Email]UserID{"Ada@x.io": 7, "ada@x.io": 8},
+			Owners:  map[Email]UserID{"Ada@x.io": 7, "ada@x.io": 8},
+			Inboxes: map[Email]int{"Bob@x.io": 3},
+		})
+		require.NoError(t, err)
+		require.Equal(t, TeamDTO{
+			Members: map[string]RoleDTO{"7": "admin", "9": "viewer"},
+			Counts:  map[string]int{"admin": 1},
+			Ports:   map[uint16]bool{8080: true},
+			Aliases: map[string]UserID{"ada@x.io": 8},
+			Owners:  map[string]UserID{"ada@x.io": 7},
+			Inboxes: map[string]int{"bob@x.io": 3},
+		}, out)
+	})
+
+	t.Run("key parse errors propagate", func(t *testing.T) {
+		_, err := m.ToDTO(Team{Ports: map[string]bool{"99999": true}})
+		require.ErrorIs(t, err, strconv.ErrRange)
+	})
+
+	t.Run("colliding keys fail by default", func(t *testing.T) {
+		_, err := m.ToDTO(Team{Inboxes: map[Email]int{"Bob@x.io": 1, "bob@x.io": 2}})
+		require.ErrorContains(t, err, "duplicate key bob@x.io")
+	})
+}
@@ -5,6 +5,13 @@
 
 package multi_param
 
+// map_Line_to_LineDTO maps a value of type Line to LineDTO.
+func map_Line_to_LineDTO(in Line) LineDTO {
+	var dst LineDTO
+	dst.SKU = in.SKU
+	return dst
+}
+
 // assemblerImpl is the generated implementation of Assembler.
 type assemblerImpl struct{}
 
@@ -19,3 +26,34 @@ func (m *assemblerImpl) Assemble(p0 Core, p1 Meta) Combined {
 	dst.Version = p1.Version
 	return dst
 }
+
+// BuildOrder maps o to the destination type.
+func (m *assemblerImpl) BuildOrder(o *Order, e *Extra) (*OrderDTO, error) {
+	if o == nil {
+		return nil, nil
+	}
+	if e == nil {
+		return nil, nil
+	}
+	dst := new(OrderDTO)
+	dst.ID = o.ID
+	tmp, err := ParseQty(o.Qty)
+	if err != nil {
+		return dst, err
+	}
+	dst.Qty = tmp
+
+	dst.Note = e.Note
+	if o.Lines != nil {
+		dst.Lines = make([]LineDTO, len(o.Lines))
+		for i, v := range o.Lines { // v used by child nodes
+			var mapped LineDTO
+			mapped = map_Line_to_LineDTO(v)
+
+			dst.Lines[i] = mapped
+		}
+	} else {
+		dst.Lines = nil
+	}
+	return dst, nil
+}
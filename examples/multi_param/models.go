@@ -1,5 +1,7 @@
 package multi_param
 
+import "strconv"
+
 //go:generate go run ../../cmd/graftgen -interface=Assembler -output=graft_gen.go
 
 type Meta struct {
@@ -17,6 +19,35 @@ type Combined struct {
 	Version string
 }
 
+type Line struct {
+	SKU string
+}
+
+type LineDTO struct {
+	SKU string
+}
+
+type Order struct {
+	ID    int
+	Qty   string
+	Lines []Line
+}
+
+type Extra struct {
+	Note string
+}
+
+type OrderDTO struct {
+	ID    int
+	Qty   int `mapfn:"ParseQty"`
+	Note  string
+	Lines []LineDTO
+}
+
+// ParseQty parses an order quantity.
+func ParseQty(s string) (int, error) { return strconv.Atoi(s) }
+
 type Assembler interface {
 	Assemble(Core, Meta) Combined
+	BuildOrder(o *Order, e *Extra) (*OrderDTO, error)
 }
@@ -1,6 +1,7 @@
 package multi_param
 
 import (
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -14,4 +15,14 @@ func TestMultiParam(t *testing.T) {
 		require.Equal(t, "X", out.Name)
 		require.Equal(t, "1", out.Version)
 	})
+
+	t.Run("pointer destinations return field errors", func(t *testing.T) {
+		m := NewAssembler()
+		out, err := m.BuildOrder(&Order{ID: 1, Qty: "2", Lines: []Line{{SKU: "a"}}}, &Extra{Note: "gift"})
+		require.NoError(t, err)
+		require.Equal(t, &OrderDTO{ID: 1, Qty: 2, Note: "gift", Lines: []LineDTO{{SKU: "a"}}}, out)
+
+		_, err = m.BuildOrder(&Order{Qty: "two"}, &Extra{})
+		require.ErrorIs(t, err, strconv.ErrSyntax)
+	})
 }
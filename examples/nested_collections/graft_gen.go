@@ -0,0 +1,161 @@
+// Code generated by graftgen (version devel); DO NOT EDIT.
+
+// Source interfaces: BoardMapper
+// Command: graftgen -interface=BoardMapper -output=graft_gen.go
+
+package nestedcollections
+
+// map_Board_to_BoardDTO maps a value of type Board to BoardDTO.
+func map_Board_to_BoardDTO(in Board) (BoardDTO, error) {
+	var dst BoardDTO
+	if in.Grid != nil {
+		dst.Grid = make([][]CellDTO, len(in.Grid))
+		for i, v := range in.Grid { // v used by child nodes
+			var mapped []CellDTO
+			if v != nil {
+				mapped = make([]CellDTO, len(v))
+				for i1, v1 := range v { // v1 used by child nodes
+					var mapped1 CellDTO
+					mapped1 = map_Cell_to_CellDTO(v1)
+
+					mapped[i1] = mapped1
+				}
+			} else {
+				mapped = nil
+			}
+			dst.Grid[i] = mapped
+		}
+	} else {
+		dst.Grid = nil
+	}
+	if in.Groups != nil {
+		dst.Groups = make(map[string][]ItemDTO, len(in.Groups))
+		for k, v := range in.Groups { // k,v used by child nodes
+			var mapped []ItemDTO
+			if v != nil {
+				mapped = make([]ItemDTO, len(v))
+				for i1, v1 := range v { // v1 used by child nodes
+					var mapped1 ItemDTO
+					mapped1 = map_Item_to_ItemDTO(v1)
+
+					mapped[i1] = mapped1
+				}
+			} else {
+				mapped = nil
+			}
+			dst.Groups[k] = mapped
+		}
+	} else {
+		dst.Groups = nil
+	}
+	if in.Pages != nil {
+		dst.Pages = make([]map[string]ItemDTO, len(in.Pages))
+		for i, v := range in.Pages { // v used by child nodes
+			var mapped map[string]ItemDTO
+			if v != nil {
+				mapped = make(map[string]ItemDTO, len(v))
+				for k1, v1 := range v { // k1,v1 used by child nodes
+					var mapped1 ItemDTO
+					mapped1 = map_Item_to_ItemDTO(v1)
+
+					mapped[k1] = mapped1
+				}
+			} else {
+				mapped = nil
+			}
+			dst.Pages[i] = mapped
+		}
+	} else {
+		dst.Pages = nil
+	}
+	if in.Refs != nil {
+		dst.Refs = make([]ItemDTO, len(in.Refs))
+		for i, v := range in.Refs { // v used by child nodes
+			var mapped ItemDTO
+			mapped = map_Ptr_Item_to_ItemDTO(v)
+
+			dst.Refs[i] = mapped
+		}
+	} else {
+		dst.Refs = nil
+	}
+	for i := range in.Totals {
+		for i1 := range in.Totals[i] {
+			tmp, err := CentsToAmount(in.Totals[i][i1])
+			if err != nil {
+				return dst, err
+			}
+			dst.Totals[i][i1] = tmp
+
+		}
+	}
+	if in.Tags != nil {
+		dst.Tags = make(map[string]map[string][]Amount, len(in.Tags))
+		for k, v := range in.Tags { // k,v used by child nodes
+			var mapped map[string][]Amount
+			if v != nil {
+				mapped = make(map[string][]Amount, len(v))
+				for k1, v1 := range v { // k1,v1 used by child nodes
+					var mapped1 []Amount
+					if v1 != nil {
+						mapped1 = make([]Amount, len(v1))
+						for i2, v2 := range v1 { // v2 used by child nodes
+							var mapped2 Amount
+							tmp1, err := CentsToAmount(v2)
+							if err != nil {
+								return dst, err
+							}
+							mapped2 = tmp1
+
+							mapped1[i2] = mapped2
+						}
+					} else {
+						mapped1 = nil
+					}
+					mapped[k1] = mapped1
+				}
+			} else {
+				mapped = nil
+			}
+			dst.Tags[k] = mapped
+		}
+	} else {
+		dst.Tags = nil
+	}
+	return dst, nil
+}
+
+// map_Cell_to_CellDTO maps a value of type Cell to CellDTO.
+func map_Cell_to_CellDTO(in Cell) CellDTO {
+	var dst CellDTO
+	dst.Value = in.Value
+	return dst
+}
+
+// map_Item_to_ItemDTO maps a value of type Item to ItemDTO.
+func map_Item_to_ItemDTO(in Item) ItemDTO {
+	var dst ItemDTO
+	dst.Name = in.Name
+	return dst
+}
+
+// map_Ptr_Item_to_ItemDTO maps a value of type *Item to ItemDTO.
+func map_Ptr_Item_to_ItemDTO(in *Item) ItemDTO {
+	if in == nil {
+		return ItemDTO{}
+	}
+	var dst ItemDTO
+	dst.Name = in.Name
+	return dst
+}
+
+// boardMapperImpl is the generated implementation of BoardMapper.
+type boardMapperImpl struct{}
+
+// NewBoardMapper returns a new BoardMapper implementation.
+func NewBoardMapper() BoardMapper { return &boardMapperImpl{} }
+
+// ToDTO maps b to the destination type.
+func (m *boardMapperImpl) ToDTO(b Board) (BoardDTO, error) {
+	return map_Board_to_BoardDTO(b)
+}
@@ -0,0 +1,51 @@
+package nestedcollections
+
+import "errors"
+
+//go:generate go run ../../cmd/graftgen -interface=BoardMapper -output=graft_gen.go
+
+var ErrNegative = errors.New("negative amount")
+
+type Cents int
+
+type Amount float64
+
+// CentsToAmount converts cents, rejecting negative values.
+func CentsToAmount(c Cents) (Amount, error) {
+	if c < 0 {
+		return 0, ErrNegative
+	}
+	return Amount(c) / 100, nil
+}
+
+type Cell struct{ Value string }
+
+type CellDTO struct{ Value string }
+
+type Item struct{ Name string }
+
+type ItemDTO struct{ Name string }
+
+type Rows [][]Cell
+
+type Board struct {
+	Grid   Rows
+	Groups map[string][]Item
+	Pages  []map[string]Item
+	Refs   []*Item
+	Totals [2][3]Cents
+	Tags   map[string]map[string][]Cents
+}
+
+type BoardDTO struct {
+	Grid   [][]CellDTO
+	Groups map[string][]ItemDTO
+	Pages  []map[string]ItemDTO
+	Refs   []ItemDTO
+	Totals [2][3]Amount
+	Tags   map[string]map[string][]Amount
+}
+
+type BoardMapper interface {
+	ToDTO(b Board) (BoardDTO, error)
+}
@@ -0,0 +1,43 @@
+package nestedcollections
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNestedCollections(t *testing.T) {
+	m := NewBoardMapper()
+
+	t.Run("maps every nesting level", func(t *testing.T) {
+		out, err := m.ToDTO(Board{
+			Grid:   Rows{{{Value: "a"}, {Value: "b"}}, nil},
+			Groups: map[string][]Item{"x": {{Name: "i"}}},
+			Pages:  []map[string]Item{{"p": {Name: "j"}}},
+			Refs:   []*Item{{Name: "k"}, nil},
+			Totals: [2][3]Cents{{100, 250}, {5}},
+			Tags:   map[string]map[string][]Cents{"t": {"u": {300}}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, BoardDTO{
+			Grid:   [][]CellDTO{{{Value: "a"}, {Value: "b"}}, nil},
+			Groups: map[string][]ItemDTO{"x": {{Name: "i"}}},
+			Pages:  []map[string]ItemDTO{{"p": {Name: "j"}}},
+			Refs:   []ItemDTO{{Name: "k"}, {}},
+			Totals: [2][3]Amount{{1, 2.5}, {0.05}},
+			Tags:   map[string]map[string][]Amount{"t": {"u": {3}}},
+		}, out)
+	})
+
+	t.Run("nil collections stay nil", func(t *testing.T) {
+		out, err := m.ToDTO(Board{})
+		require.NoError(t, err)
+		require.Nil(t, out.Grid)
+		require.Nil(t, out.Tags)
+	})
+
+	t.Run("errors short-circuit from inner levels", func(t *testing.T) {
+		_, err := m.ToDTO(Board{Tags: map[string]map[string][]Cents{"t": {"u": {1, -1}}}})
+		require.ErrorIs(t, err, ErrNegative)
+	})
+}
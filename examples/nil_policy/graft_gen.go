@@ -0,0 +1,197 @@
+// Code generated by graftgen (version devel); DO NOT EDIT.
+
+// Source interfaces: FeedMapper
+// Command: graftgen -interface=FeedMapper -output=graft_gen.go -nil=empty
+
+package nilpolicy
+
+// mapc_Slice_Item_to_Slice_ItemDTO_keep maps a value of type []Item to []ItemDTO.
+func mapc_Slice_Item_to_Slice_ItemDTO_keep(in []Item) []ItemDTO {
+	var dst []ItemDTO
+	if in != nil {
+		dst = make([]ItemDTO, len(in))
+		for i, v := range in { // v used by child nodes
+			var mapped ItemDTO
+			mapped = map_Item_to_ItemDTO_keep(v)
+
+			dst[i] = mapped
+		}
+	} else {
+		dst = nil
+	}
+	return dst
+}
+
+// map_Feed_to_FeedDTO maps a value of type Feed to FeedDTO.
+func map_Feed_to_FeedDTO(in Feed) FeedDTO {
+	var dst FeedDTO
+	if in.Items != nil {
+		dst.Items = make([]ItemDTO, len(in.Items))
+		for i, v := range in.Items { // v used by child nodes
+			var mapped ItemDTO
+			mapped = map_Item_to_ItemDTO(v)
+
+			dst.Items[i] = mapped
+		}
+	} else {
+		dst.Items = []ItemDTO{}
+	}
+	if in.Meta != nil {
+		dst.Meta = make(map[string]string, len(in.Meta))
+		for k, v := range in.Meta { // k,v used by child nodes
+			var mapped string
+			mapped = v
+			dst.Meta[k] = mapped
+		}
+	} else {
+		dst.Meta = map[string]string{}
+	}
+	if in.Groups != nil {
+		dst.Groups = make(map[string][]ItemDTO, len(in.Groups))
+		for k, v := range in.Groups { // k,v used by child nodes
+			var mapped []ItemDTO
+			if v != nil {
+				mapped = make([]ItemDTO, len(v))
+				for i1, v1 := range v { // v1 used by child nodes
+					var mapped1 ItemDTO
+					mapped1 = map_Item_to_ItemDTO(v1)
+
+					mapped[i1] = mapped1
+				}
+			} else {
+				mapped = []ItemDTO{}
+			}
+			dst.Groups[k] = mapped
+		}
+	} else {
+		dst.Groups = map[string][]ItemDTO{}
+	}
+	dst.Cursors = in.Cursors
+	if in.Pinned != nil {
+		dst.Pinned = make([]ItemDTO, len(in.Pinned))
+		for i, v := range in.Pinned { // v used by child nodes
+			var mapped ItemDTO
+			mapped = map_Item_to_ItemDTO_keep(v)
+
+			dst.Pinned[i] = mapped
+		}
+	} else {
+		dst.Pinned = nil
+	}
+	return dst
+}
+
+// map_Item_to_ItemDTO_keep maps a value of type Item to ItemDTO.
+func map_Item_to_ItemDTO_keep(in Item) ItemDTO {
+	var dst ItemDTO
+	dst.ID = in.ID
+	dst.Tags = in.Tags
+	return dst
+}
+
+// map_Item_to_ItemDTO maps a value of type Item to ItemDTO.
+func map_Item_to_ItemDTO(in Item) ItemDTO {
+	var dst ItemDTO
+	dst.ID = in.ID
+	if in.Tags != nil {
+		dst.Tags = make([]string, len(in.Tags))
+		for i, v := range in.Tags { // v used by child nodes
+			var mapped string
+			mapped = v
+			dst.Tags[i] = mapped
+		}
+	} else {
+		dst.Tags = []string{}
+	}
+	return dst
+}
+
+// map_Item_to_ItemDTO_nil maps a value of type Item to ItemDTO.
+func map_Item_to_ItemDTO_nil(in Item) ItemDTO {
+	var dst ItemDTO
+	dst.ID = in.ID
+	if len(in.Tags) > 0 {
+		dst.Tags = make([]string, len(in.Tags))
+		for i, v := range in.Tags { // v used by child nodes
+			var mapped string
+			mapped = v
+			dst.Tags[i] = mapped
+		}
+	} else {
+		dst.Tags = nil
+	}
+	return dst
+}
+
+// feedMapperImpl is the generated implementation of FeedMapper.
+type feedMapperImpl struct{}
+
+// NewFeedMapper returns a new FeedMapper implementation.
+func NewFeedMapper() FeedMapper { return &feedMapperImpl{} }
+
+// ItemsToDTO maps p0 to the destination type.
+func (m *feedMapperImpl) ItemsToDTO(p0 []Item) []ItemDTO {
+	return mapc_Slice_Item_to_Slice_ItemDTO_keep(p0)
+}
+
+// ToDTO maps p0 to the destination type.
+func (m *feedMapperImpl) ToDTO(p0 Feed) FeedDTO {
+	return map_Feed_to_FeedDTO(p0)
+}
+
+// ToRecord maps p0 to the destination type.
+func (m *feedMapperImpl) ToRecord(p0 Feed) FeedRecord {
+	var dst FeedRecord
+	if len(p0.Items) > 0 {
+		dst.Items = make([]ItemDTO, len(p0.Items))
+		for i, v := range p0.Items { // v used by child nodes
+			var mapped ItemDTO
+			mapped = map_Item_to_ItemDTO_nil(v)
+
+			dst.Items[i] = mapped
+		}
+	} else {
+		dst.Items = nil
+	}
+	if len(p0.Meta) > 0 {
+		dst.Meta = make(map[string]string, len(p0.Meta))
+		for k, v := range p0.Meta { // k,v used by child nodes
+			var mapped string
+			mapped = v
+			dst.Meta[k] = mapped
+		}
+	} else {
+		dst.Meta = nil
+	}
+	if len(p0.Groups) > 0 {
+		dst.Groups = make(map[string][]ItemDTO, len(p0.Groups))
+		for k, v := range p0.Groups { // k,v used by child nodes
+			var mapped []ItemDTO
+			if len(v) > 0 {
+				mapped = make([]ItemDTO, len(v))
+				for i1, v1 := range v { // v1 used by child nodes
+					var mapped1 ItemDTO
+					mapped1 = map_Item_to_ItemDTO_nil(v1)
+
+					mapped[i1] = mapped1
+				}
+			} else {
+				mapped = nil
+			}
+			dst.Groups[k] = mapped
+		}
+	} else {
+		dst.Groups = nil
+	}
+	if len(p0.Cursors) > 0 {
+		dst.Cursors = make([]string, len(p0.Cursors))
+		for i, v := range p0.Cursors { // v used by child nodes
+			var mapped string
+			mapped = v
+			dst.Cursors[i] = mapped
+		}
+	} else {
+		dst.Cursors = nil
+	}
+	return dst
+}
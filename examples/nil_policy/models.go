@@ -0,0 +1,44 @@
+package nilpolicy
+
+//go:generate go run ../../cmd/graftgen -interface=FeedMapper -output=graft_gen.go -nil=empty
+
+type Item struct {
+	ID   string
+	Tags []string
+}
+
+type ItemDTO struct {
+	ID   string
+	Tags []string
+}
+
+type Feed struct {
+	Items   []Item
+	Meta    map[string]string
+	Groups  map[string][]Item
+	Cursors []string
+	Pinned  []Item
+}
+
+type FeedDTO struct {
+	Items   []ItemDTO
+	Meta    map[string]string
+	Groups  map[string][]ItemDTO
+	Cursors []string  `mapnil:"keep"`
+	Pinned  []ItemDTO `mapnil:"keep"`
+}
+
+type FeedRecord struct {
+	Items   []ItemDTO
+	Meta    map[string]string
+	Groups  map[string][]ItemDTO
+	Cursors []string
+}
+
+type FeedMapper interface {
+	ToDTO(Feed) FeedDTO
+	//graft:nil nil
+	ToRecord(Feed) FeedRecord
+	//graft:nil keep
+	ItemsToDTO([]Item) []ItemDTO
+}
@@ -0,0 +1,40 @@
+package nilpolicy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNilPolicy(t *testing.T) {
+	m := NewFeedMapper()
+
+	t.Run("nil collections become empty", func(t *testing.T) {
+		out := m.ToDTO(Feed{Items: []Item{{ID: "a"}}, Groups: map[string][]Item{"g": nil}})
+		require.Equal(t, []ItemDTO{{ID: "a", Tags: []string{}}}, out.Items)
+		require.Equal(t, map[string]string{}, out.Meta)
+		require.Equal(t, map[string][]ItemDTO{"g": {}}, out.Groups)
+		require.Nil(t, out.Cursors)
+	})
+
+	t.Run("fields pass their policy to nested structs", func(t *testing.T) {
+		out := m.ToDTO(Feed{Pinned: []Item{{ID: "p"}}})
+		require.Equal(t, []ItemDTO{{ID: "p"}}, out.Pinned)
+	})
+
+	t.Run("empty collections become nil", func(t *testing.T) {
+		out := m.ToRecord(Feed{Items: []Item{}, Meta: map[string]string{}, Cursors: []string{"c"}})
+		require.Nil(t, out.Items)
+		require.Nil(t, out.Meta)
+		require.Equal(t, []string{"c"}, out.Cursors)
+
+		out = m.ToRecord(Feed{Items: []Item{{ID: "a", Tags: []string{}}}})
+		require.Equal(t, []ItemDTO{{ID: "a"}}, out.Items)
+	})
+
+	t.Run("methods keep their own policy", func(t *testing.T) {
+		require.Nil(t, m.ItemsToDTO(nil))
+		require.Equal(t, []ItemDTO{}, m.ItemsToDTO([]Item{}))
+		require.Equal(t, []ItemDTO{{ID: "a"}}, m.ItemsToDTO([]Item{{ID: "a"}}))
+	})
+}
@@ -0,0 +1,7 @@
+// Package errs holds the sentinel errors shared by the drawing services.
+package errs
+
+import "errors"
+
+// ErrUnknown is returned for values of an unknown kind.
+var ErrUnknown = errors.New("unknown kind")
@@ -0,0 +1,141 @@
+// Code generated by graftgen (version devel); DO NOT EDIT.
+
+// Source interfaces: DrawingMapper, ShapeMapper
+// Command: graftgen -interface=DrawingMapper,ShapeMapper -output=graft_gen.go
+
+package polymorphic
+
+import (
+	"fmt"
+	"github.com/calumari/graft/examples/polymorphic/errs"
+)
+
+// map_Drawing_to_DrawingDTO maps a value of type Drawing to DrawingDTO.
+func map_Drawing_to_DrawingDTO(in Drawing) (DrawingDTO, error) {
+	var dst DrawingDTO
+	switch v := in.Main.(type) {
+	case nil:
+	case Circle:
+		dst.Main = CircleToDTO(v)
+
+	case *Square:
+		if v != nil {
+			dst.Main = SquareToDTO(*v)
+		}
+	case Triangle:
+		dst.Main = TriangleToDTO(v)
+
+	default:
+		return dst, fmt.Errorf("mapping Main: unexpected type %T", v)
+	}
+	if in.Layers != nil {
+		dst.Layers = make([]ShapeDTO, len(in.Layers))
+		for i, v := range in.Layers { // v used by child nodes
+			var mapped ShapeDTO
+			switch v1 := v.(type) {
+			case nil:
+			case Circle:
+				mapped = CircleToDTO(v1)
+
+			case *Square:
+				if v1 != nil {
+					mapped = SquareToDTO(*v1)
+				}
+			case Triangle:
+				mapped = TriangleToDTO(v1)
+
+			default:
+				return dst, fmt.Errorf("mapping Layers: unexpected type %T", v1)
+			}
+			dst.Layers[i] = mapped
+		}
+	} else {
+		dst.Layers = nil
+	}
+	switch v := in.Pin.(type) {
+	case nil:
+	case Circle:
+		dst.Pin = CircleToDTO(v)
+
+	case *Square:
+		if v != nil {
+			dst.Pin = SquareToDTO(*v)
+		}
+	default:
+		return dst, fmt.Errorf("mapping Pin: %w: %T", ErrUnknownShape, v)
+	}
+	switch v := in.Overlay.(type) {
+	case nil:
+	case Circle:
+		dst.Overlay = CircleToDTO(v)
+
+	case *Square:
+		if v != nil {
+			dst.Overlay = SquareToDTO(*v)
+		}
+	}
+	switch v := in.Hint.(type) {
+	case nil:
+	case Circle:
+		dst.Hint = CircleToDTO(v)
+
+	default:
+		return dst, fmt.Errorf("mapping Hint: unexpected type %T", v)
+	}
+	switch v := in.Badge.(type) {
+	case nil:
+	case Circle:
+		dst.Badge = CircleToDTO(v)
+
+	case *Square:
+		if v != nil {
+			dst.Badge = SquareToDTO(*v)
+		}
+	default:
+		return dst, fmt.Errorf("mapping Badge: %w: %T", errs.ErrUnknown, v)
+	}
+	return dst, nil
+}
+
+// mapc_Shape_to_ShapeDTO maps a value of type Shape to ShapeDTO.
+func mapc_Shape_to_ShapeDTO(in Shape) (ShapeDTO, error) {
+	var dst ShapeDTO
+	switch v := in.(type) {
+	case nil:
+	case Circle:
+		dst = CircleToDTO(v)
+
+	case *Square:
+		if v != nil {
+			dst = SquareToDTO(*v)
+		}
+	case Triangle:
+		dst = TriangleToDTO(v)
+
+	default:
+		return dst, fmt.Errorf("mapping Shape: unexpected type %T", v)
+	}
+	return dst, nil
+}
+
+// drawingMapperImpl is the generated implementation of DrawingMapper.
+type drawingMapperImpl struct{}
+
+// NewDrawingMapper returns a new DrawingMapper implementation.
+func NewDrawingMapper() DrawingMapper { return &drawingMapperImpl{} }
+
+// ToDTO maps p0 to the destination type.
+func (m *drawingMapperImpl) ToDTO(p0 Drawing) (DrawingDTO, error) {
+	return map_Drawing_to_DrawingDTO(p0)
+}
+
+// shapeMapperImpl is the generated implementation of ShapeMapper.
+type shapeMapperImpl struct{}
+
+// NewShapeMapper returns a new ShapeMapper implementation.
+func NewShapeMapper() ShapeMapper { return &shapeMapperImpl{} }
+
+// ShapeToDTO maps p0 to the destination type.
+func (m *shapeMapperImpl) ShapeToDTO(p0 Shape) (ShapeDTO, error) {
+	return mapc_Shape_to_ShapeDTO(p0)
+}
@@ -0,0 +1,78 @@
+package polymorphic
+
+import (
+	"fmt"
+
+	"github.com/calumari/graft/examples/polymorphic/errs"
+)
+
+//go:generate go run ../../cmd/graftgen -interface=DrawingMapper,ShapeMapper -output=graft_gen.go
+
+// Shape is implemented by Circle, *Square and Triangle.
+//
+//graft:variants auto
+type Shape interface{ Area() float64 }
+
+type Circle struct{ Radius float64 }
+
+func (c Circle) Area() float64 { return 3 * c.Radius * c.Radius }
+
+type Square struct{ Side float64 }
+
+func (s *Square) Area() float64 { return s.Side * s.Side }
+
+// Triangle implements Shape but is left out of the //graft:variants list of
+// Marker.
+type Triangle struct{ Base, Height float64 }
+
+func (t Triangle) Area() float64 { return t.Base * t.Height / 2 }
+
+// Marker is a shape variant placed on a drawing.
+//
+//graft:variants Circle *Square
+type Marker interface{ Area() float64 }
+
+// ShapeDTO is the flattened wire form of every shape.
+type ShapeDTO struct {
+	Kind   string
+	Radius float64
+	Side   float64
+}
+
+// CircleToDTO converts a circle.
+func CircleToDTO(c Circle) ShapeDTO { return ShapeDTO{Kind: "circle", Radius: c.Radius} }
+
+// SquareToDTO converts a square.
+func SquareToDTO(s Square) ShapeDTO { return ShapeDTO{Kind: "square", Side: s.Side} }
+
+// TriangleToDTO converts a triangle.
+func TriangleToDTO(t Triangle) ShapeDTO { return ShapeDTO{Kind: "triangle"} }
+
+// ErrUnknownShape is returned for shapes without a mapping.
+var ErrUnknownShape = fmt.Errorf("%w: shape", errs.ErrUnknown)
+
+type Drawing struct {
+	Main    Shape
+	Layers  []Shape
+	Pin     Marker
+	Overlay Marker
+	Hint    Shape
+	Badge   Marker
+}
+
+type DrawingDTO struct {
+	Main    ShapeDTO
+	Layers  []ShapeDTO
+	Pin     ShapeDTO `mapunknown:"ErrUnknownShape"`
+	Overlay ShapeDTO `mapunknown:"skip"`
+	Hint    ShapeDTO `mapvariants:"Circle"`
+	Badge   ShapeDTO `mapunknown:"errs.ErrUnknown"`
+}
+
+type DrawingMapper interface {
+	ToDTO(Drawing) (DrawingDTO, error)
+}
+
+type ShapeMapper interface {
+	ShapeToDTO(Shape) (ShapeDTO, error)
+}
@@ -0,0 +1,60 @@
+package polymorphic
+
+import (
+	"testing"
+
+	"github.com/calumari/graft/examples/polymorphic/errs"
+	"github.com/stretchr/testify/require"
+)
+
+// hexagon implements Shape outside the generated switch.
+type hexagon struct{}
+
+func (hexagon) Area() float64 { return 0 }
+
+func TestPolymorphic(t *testing.T) {
+	m := NewDrawingMapper()
+
+	t.Run("dispatches on the dynamic type", func(t *testing.T) {
+		out, err := m.ToDTO(Drawing{
+			Main:    Circle{Radius: 2},
+			Layers:  []Shape{&Square{Side: 3}, nil, Triangle{}},
+			Pin:     &Square{Side: 1},
+			Overlay: Triangle{},
+			Hint:    Circle{Radius: 1},
+		})
+		require.NoError(t, err)
+		require.Equal(t, ShapeDTO{Kind: "circle", Radius: 2}, out.Main)
+		require.Equal(t, []ShapeDTO{{Kind: "square", Side: 3}, {}, {Kind: "triangle"}}, out.Layers)
+		require.Equal(t, ShapeDTO{Kind: "square", Side: 1}, out.Pin)
+		require.Equal(t, ShapeDTO{}, out.Overlay)
+		require.Equal(t, ShapeDTO{Kind: "circle", Radius: 1}, out.Hint)
+	})
+
+	t.Run("nil sources map to the zero value", func(t *testing.T) {
+		out, err := m.ToDTO(Drawing{})
+		require.NoError(t, err)
+		require.Equal(t, DrawingDTO{}, out)
+	})
+
+	t.Run("unknown types fail", func(t *testing.T) {
+		_, err := m.ToDTO(Drawing{Hint: &Square{}})
+		require.EqualError(t, err, "mapping Hint: unexpected type *polymorphic.Square")
+
+		_, err = m.ToDTO(Drawing{Layers: []Shape{hexagon{}}})
+		require.EqualError(t, err, "mapping Layers: unexpected type polymorphic.hexagon")
+
+		_, err = m.ToDTO(Drawing{Pin: Triangle{}})
+		require.ErrorIs(t, err, ErrUnknownShape)
+
+		_, err = m.ToDTO(Drawing{Badge: Triangle{}})
+		require.EqualError(t, err, "mapping Badge: unknown kind: polymorphic.Triangle")
+		require.ErrorIs(t, err, errs.ErrUnknown)
+	})
+
+	t.Run("methods take an interface directly", func(t *testing.T) {
+		out, err := NewShapeMapper().ShapeToDTO(Triangle{})
+		require.NoError(t, err)
+		require.Equal(t, ShapeDTO{Kind: "triangle"}, out)
+	})
+}
@@ -62,10 +62,10 @@ func (m *userMapperImpl) ToDTOFromMultipleUsers(p0 *User, p1 *User) *UserDTO {
 	if p1 == nil {
 		return nil
 	}
-	mapped := new(UserDTO)
-	mapped.ID = p0.ID
-	mapped.Name = p0.Name
-	return mapped
+	dst := new(UserDTO)
+	dst.ID = p0.ID
+	dst.Name = p0.Name
+	return dst
 }
 
 // ToDTOFromPtr maps p0 to the destination type.
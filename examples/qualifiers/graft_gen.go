@@ -0,0 +1,50 @@
+// Code generated by graftgen (version devel); DO NOT EDIT.
+
+// Source interfaces: EventMapper
+// Command: graftgen -interface=EventMapper -output=graft_gen.go
+
+package qualifiers
+
+// map_Event_to_EventDTO maps a value of type Event to EventDTO.
+func map_Event_to_EventDTO(in Event) EventDTO {
+	var dst EventDTO
+	dst.Name = in.Name
+	dst.Start = FormatDate(in.Start)
+
+	if in.Dates != nil {
+		dst.Dates = make([]string, len(in.Dates))
+		for i, v := range in.Dates { // v used by child nodes
+			var mapped string
+			mapped = FormatDate(v)
+
+			dst.Dates[i] = mapped
+		}
+	} else {
+		dst.Dates = nil
+	}
+	dst.Created = FormatISO(in.Created)
+
+	return dst
+}
+
+// eventMapperImpl is the generated implementation of EventMapper.
+type eventMapperImpl struct{}
+
+// NewEventMapper returns a new EventMapper implementation.
+func NewEventMapper() EventMapper { return &eventMapperImpl{} }
+
+// ToCard maps e to the destination type.
+func (m *eventMapperImpl) ToCard(e Event) Card {
+	var dst Card
+	dst.Name = e.Name
+	dst.Start = FormatHuman(e.Start)
+
+	dst.Created = FormatHuman(e.Created)
+
+	return dst
+}
+
+// ToDTO maps e to the destination type.
+func (m *eventMapperImpl) ToDTO(e Event) EventDTO {
+	return map_Event_to_EventDTO(e)
+}
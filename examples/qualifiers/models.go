@@ -0,0 +1,44 @@
+package qualifiers
+
+import "time"
+
+//go:generate go run ../../cmd/graftgen -interface=EventMapper -output=graft_gen.go
+
+// FormatISO is the default time conversion.
+func FormatISO(t time.Time) string { return t.Format(time.RFC3339) }
+
+// FormatDate renders the date only.
+//
+//graft:named short
+func FormatDate(t time.Time) string { return t.Format(time.DateOnly) }
+
+// FormatHuman renders a date for display.
+//
+//graft:named human
+func FormatHuman(t time.Time) string { return t.Format("Jan 2, 2006") }
+
+type Event struct {
+	Name    string
+	Start   time.Time
+	Dates   []time.Time
+	Created time.Time
+}
+
+type EventDTO struct {
+	Name    string
+	Start   string   `mapfn:"@short"`
+	Dates   []string `mapfn:"@short"`
+	Created string
+}
+
+type Card struct {
+	Name    string
+	Start   string
+	Created string
+}
+
+type EventMapper interface {
+	ToDTO(e Event) EventDTO
+	//graft:use human
+	ToCard(e Event) Card
+}
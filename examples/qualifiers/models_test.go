@@ -0,0 +1,29 @@
+package qualifiers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQualifiers(t *testing.T) {
+	m := NewEventMapper()
+	start := time.Date(2024, 3, 9, 18, 30, 0, 0, time.UTC)
+	created := time.Date(2024, 1, 2, 8, 0, 0, 0, time.UTC)
+	ev := Event{Name: "launch", Start: start, Dates: []time.Time{start, created}, Created: created}
+
+	t.Run("field qualifiers select named converters", func(t *testing.T) {
+		out := m.ToDTO(ev)
+		require.Equal(t, "2024-03-09", out.Start)
+		require.Equal(t, []string{"2024-03-09", "2024-01-02"}, out.Dates)
+	})
+
+	t.Run("unqualified fields use the unnamed converter", func(t *testing.T) {
+		require.Equal(t, "2024-01-02T08:00:00Z", m.ToDTO(ev).Created)
+	})
+
+	t.Run("method qualifier applies to every field", func(t *testing.T) {
+		require.Equal(t, Card{Name: "launch", Start: "Mar 9, 2024", Created: "Jan 2, 2024"}, m.ToCard(ev))
+	})
+}
@@ -0,0 +1,160 @@
+// Code generated by graftgen (version devel); DO NOT EDIT.
+
+// Source interfaces: RequestMapper
+// Command: graftgen -interface=RequestMapper -output=graft_gen.go
+
+package query
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// map_url_Values_to_ListUsersRequest_query maps a value of type url.Values to ListUsersRequest.
+func map_url_Values_to_ListUsersRequest_query(in url.Values) (ListUsersRequest, error) {
+	var dst ListUsersRequest
+	if vs := in["page"]; len(vs) > 0 {
+		x, err := strconv.Atoi(vs[0])
+		if err != nil {
+			return dst, fmt.Errorf("key %q: %w", "page", err)
+		}
+		dst.Page = x
+	}
+	if vs := in["per_page"]; len(vs) > 0 {
+		n, err := strconv.ParseUint(vs[0], 10, 16)
+		if err != nil {
+			return dst, fmt.Errorf("key %q: %w", "per_page", err)
+		}
+		x := uint16(n)
+		dst.PerPage = x
+	}
+	if vs := in["sort"]; len(vs) > 0 {
+		dst.Sort = SortOrder(vs[0])
+	}
+	if vs := in["active"]; len(vs) > 0 {
+		x, err := strconv.ParseBool(vs[0])
+		if err != nil {
+			return dst, fmt.Errorf("key %q: %w", "active", err)
+		}
+		dst.Active = &x
+	}
+	if vs := in["min_score"]; len(vs) > 0 {
+		x, err := strconv.ParseFloat(vs[0], 64)
+		if err != nil {
+			return dst, fmt.Errorf("key %q: %w", "min_score", err)
+		}
+		dst.MinScore = x
+	}
+	if vs := in["since"]; len(vs) > 0 {
+		var x time.Time
+		if err := x.UnmarshalText([]byte(vs[0])); err != nil {
+			return dst, fmt.Errorf("key %q: %w", "since", err)
+		}
+		dst.Since = x
+	}
+	if vs := in["timeout"]; len(vs) > 0 {
+		x, err := time.ParseDuration(vs[0])
+		if err != nil {
+			return dst, fmt.Errorf("key %q: %w", "timeout", err)
+		}
+		dst.Timeout = x
+	}
+	if vs := in["level"]; len(vs) > 0 {
+		var x Level
+		if err := x.UnmarshalText([]byte(vs[0])); err != nil {
+			return dst, fmt.Errorf("key %q: %w", "level", err)
+		}
+		dst.Level = x
+	}
+	if vs := in["tag"]; len(vs) > 0 {
+		dst.Tags = make([]string, 0, len(vs))
+		for _, s := range vs {
+			dst.Tags = append(dst.Tags, s)
+		}
+	}
+	if vs := in["id"]; len(vs) > 0 {
+		dst.IDs = make([]int64, 0, len(vs))
+		for _, s := range vs {
+			x, err := strconv.ParseInt(s, 10, 64)
+			if err != nil {
+				return dst, fmt.Errorf("key %q: %w", "id", err)
+			}
+			dst.IDs = append(dst.IDs, x)
+		}
+	}
+	return dst, nil
+}
+
+// map_http_Header_to_Ptr_RequestMeta_header maps a value of type http.Header to *RequestMeta.
+func map_http_Header_to_Ptr_RequestMeta_header(in http.Header) (*RequestMeta, error) {
+	dst := new(RequestMeta)
+	if vs := in["X-Request-Id"]; len(vs) > 0 {
+		dst.RequestID = vs[0]
+	}
+	if vs := in["X-Retries"]; len(vs) > 0 {
+		x, err := strconv.Atoi(vs[0])
+		if err != nil {
+			return dst, fmt.Errorf("key %q: %w", "X-Retries", err)
+		}
+		dst.Retries = x
+	}
+	if vs := in["Accept"]; len(vs) > 0 {
+		dst.Accept = make([]string, 0, len(vs))
+		for _, s := range vs {
+			dst.Accept = append(dst.Accept, s)
+		}
+	}
+	return dst, nil
+}
+
+// map_Search_to_SearchRequest maps a value of type Search to SearchRequest.
+func map_Search_to_SearchRequest(in Search) (SearchRequest, error) {
+	var dst SearchRequest
+	tmp, err := map_url_Values_to_Filters(in.Query)
+	if err != nil {
+		return dst, err
+	}
+	dst.Query = tmp
+
+	return dst, nil
+}
+
+// map_url_Values_to_Filters maps a value of type url.Values to Filters.
+func map_url_Values_to_Filters(in url.Values) (Filters, error) {
+	var dst Filters
+	if vs := in["Term"]; len(vs) > 0 {
+		dst.Term = vs[0]
+	}
+	if vs := in["Limit"]; len(vs) > 0 {
+		x, err := strconv.Atoi(vs[0])
+		if err != nil {
+			return dst, fmt.Errorf("key %q: %w", "Limit", err)
+		}
+		dst.Limit = &x
+	}
+	return dst, nil
+}
+
+// requestMapperImpl is the generated implementation of RequestMapper.
+type requestMapperImpl struct{}
+
+// NewRequestMapper returns a new RequestMapper implementation.
+func NewRequestMapper() RequestMapper { return &requestMapperImpl{} }
+
+// ListUsers maps p0 to the destination type.
+func (m *requestMapperImpl) ListUsers(p0 url.Values) (ListUsersRequest, error) {
+	return map_url_Values_to_ListUsersRequest_query(p0)
+}
+
+// Meta maps p0 to the destination type.
+func (m *requestMapperImpl) Meta(p0 http.Header) (*RequestMeta, error) {
+	return map_http_Header_to_Ptr_RequestMeta_header(p0)
+}
+
+// ToSearch maps p0 to the destination type.
+func (m *requestMapperImpl) ToSearch(p0 Search) (SearchRequest, error) {
+	return map_Search_to_SearchRequest(p0)
+}
@@ -0,0 +1,74 @@
+package query
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+//go:generate go run ../../cmd/graftgen -interface=RequestMapper -output=graft_gen.go
+
+// Level implements encoding.TextUnmarshaler.
+type Level int
+
+const (
+	LevelInfo Level = iota
+	LevelDebug
+)
+
+func (l *Level) UnmarshalText(b []byte) error {
+	switch string(b) {
+	case "info":
+		*l = LevelInfo
+	case "debug":
+		*l = LevelDebug
+	default:
+		return fmt.Errorf("unknown level %q", b)
+	}
+	return nil
+}
+
+type SortOrder string
+
+type ListUsersRequest struct {
+	Page     int           `query:"page"`
+	PerPage  uint16        `query:"per_page"`
+	Sort     SortOrder     `query:"sort"`
+	Active   *bool         `query:"active"`
+	MinScore float64       `query:"min_score"`
+	Since    time.Time     `query:"since"`
+	Timeout  time.Duration `query:"timeout"`
+	Level    Level         `query:"level"`
+	Tags     []string      `query:"tag"`
+	IDs      []int64       `query:"id"`
+	Internal string        `query:"-"`
+}
+
+type RequestMeta struct {
+	RequestID string `header:"x-request-id"`
+	Retries   int    `header:"x-retries"`
+	Accept    []string
+}
+
+// Search carries its filters as a raw query string.
+type Search struct {
+	Query url.Values
+}
+
+type SearchRequest struct {
+	Query Filters
+}
+
+type Filters struct {
+	Term  string
+	Limit *int
+}
+
+type RequestMapper interface {
+	//graft:keytag query
+	ListUsers(url.Values) (ListUsersRequest, error)
+	//graft:keytag header
+	Meta(http.Header) (*RequestMeta, error)
+	ToSearch(Search) (SearchRequest, error)
+}
@@ -0,0 +1,75 @@
+package query
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestMapper(t *testing.T) {
+	m := NewRequestMapper()
+
+	t.Run("query values parse by field type", func(t *testing.T) {
+		q, err := url.ParseQuery("page=2&per_page=50&sort=name&active=true&min_score=1.5&since=2024-05-01T10:00:00Z&timeout=1m30s&level=debug&tag=a&tag=b&id=7&id=9&Internal=x")
+		require.NoError(t, err)
+
+		out, err := m.ListUsers(q)
+		require.NoError(t, err)
+		active := true
+		require.Equal(t, ListUsersRequest{
+			Page:     2,
+			PerPage:  50,
+			Sort:     "name",
+			Active:   &active,
+			MinScore: 1.5,
+			Since:    time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+			Timeout:  90 * time.Second,
+			Level:    LevelDebug,
+			Tags:     []string{"a", "b"},
+			IDs:      []int64{7, 9},
+		}, out)
+	})
+
+	t.Run("missing keys stay zero", func(t *testing.T) {
+		out, err := m.ListUsers(url.Values{"page": {"3"}})
+		require.NoError(t, err)
+		require.Equal(t, ListUsersRequest{Page: 3}, out)
+	})
+
+	t.Run("parse errors name the key", func(t *testing.T) {
+		_, err := m.ListUsers(url.Values{"per_page": {"70000"}})
+		require.ErrorContains(t, err, `key "per_page": strconv.ParseUint: parsing "70000": value out of range`)
+
+		_, err = m.ListUsers(url.Values{"id": {"7", "x"}})
+		require.ErrorContains(t, err, `key "id": strconv.ParseInt: parsing "x": invalid syntax`)
+
+		_, err = m.ListUsers(url.Values{"level": {"trace"}})
+		require.EqualError(t, err, `key "level": unknown level "trace"`)
+	})
+
+	t.Run("header keys are canonicalized", func(t *testing.T) {
+		h := http.Header{}
+		h.Set("X-Request-ID", "req-1")
+		h.Set("X-Retries", "2")
+		h.Add("Accept", "text/html")
+		h.Add("Accept", "application/json")
+
+		out, err := m.Meta(h)
+		require.NoError(t, err)
+		require.Equal(t, &RequestMeta{RequestID: "req-1", Retries: 2, Accept: []string{"text/html", "application/json"}}, out)
+
+		h.Set("X-Retries", "many")
+		_, err = m.Meta(h)
+		require.ErrorContains(t, err, `key "X-Retries"`)
+	})
+
+	t.Run("values fields map to structs by field name", func(t *testing.T) {
+		out, err := m.ToSearch(Search{Query: url.Values{"Term": {"go"}, "Limit": {"10"}}})
+		require.NoError(t, err)
+		limit := 10
+		require.Equal(t, SearchRequest{Query: Filters{Term: "go", Limit: &limit}}, out)
+	})
+}
@@ -0,0 +1,228 @@
+// Code generated by graftgen (version devel); DO NOT EDIT.
+
+// Source interfaces: RecordMapper
+// Command: graftgen -interface=RecordMapper -output=graft_gen.go
+
+package records
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// map_Slice_any_to_Account maps a value of type []any to Account.
+func map_Slice_any_to_Account(in []any) (Account, error) {
+	var dst Account
+	if len(in) < 9 {
+		return dst, fmt.Errorf("record has %d columns, want %d", len(in), 9)
+	}
+	if v := in[0]; v != nil {
+		x, ok := v.(int64)
+		if !ok {
+			return dst, fmt.Errorf("column %d: want int64, have %T", 0, v)
+		}
+		dst.ID = x
+	}
+	if v := in[1]; v != nil {
+		var x string
+		switch n := v.(type) {
+		case string:
+			x = n
+		case []byte:
+			x = string(n)
+		default:
+			return dst, fmt.Errorf("column %d: want string or []byte, have %T", 1, v)
+		}
+		dst.Owner = x
+	}
+	if v := in[2]; v != nil {
+		x, ok := v.(float64)
+		if !ok {
+			return dst, fmt.Errorf("column %d: want float64, have %T", 2, v)
+		}
+		dst.Balance = &x
+	}
+	if v := in[3]; v != nil {
+		x, ok := v.(bool)
+		if !ok {
+			return dst, fmt.Errorf("column %d: want bool, have %T", 3, v)
+		}
+		dst.Active = x
+	}
+	if v := in[4]; v != nil {
+		n, ok := v.(int64)
+		if !ok {
+			return dst, fmt.Errorf("column %d: want int64, have %T", 4, v)
+		}
+		if int64(int32(n)) != n {
+			return dst, fmt.Errorf("column %d: %d overflows int32", 4, n)
+		}
+		x := int32(n)
+		dst.Tier = x
+	}
+	if v := in[5]; v != nil {
+		n, ok := v.(int64)
+		if !ok {
+			return dst, fmt.Errorf("column %d: want int64, have %T", 5, v)
+		}
+		if n < 0 || int64(uint16(n)) != n {
+			return dst, fmt.Errorf("column %d: %d overflows uint16", 5, n)
+		}
+		x := uint16(n)
+		dst.Logins = &x
+	}
+	if v := in[6]; v != nil {
+		n, ok := v.(float64)
+		if !ok {
+			return dst, fmt.Errorf("column %d: want float64, have %T", 6, v)
+		}
+		x := float32(n)
+		dst.Rate = x
+	}
+	if v := in[7]; v != nil {
+		var x Status
+		switch n := v.(type) {
+		case string:
+			x = Status(n)
+		case []byte:
+			x = Status(n)
+		default:
+			return dst, fmt.Errorf("column %d: want string or []byte, have %T", 7, v)
+		}
+		dst.Status = x
+	}
+	if v := in[8]; v != nil {
+		x, ok := v.(time.Time)
+		if !ok {
+			return dst, fmt.Errorf("column %d: want time.Time, have %T", 8, v)
+		}
+		dst.Opened = x
+	}
+	return dst, nil
+}
+
+// map_Slice_string_to_Trade maps a value of type []string to Trade.
+func map_Slice_string_to_Trade(in []string) (Trade, error) {
+	var dst Trade
+	if len(in) < 5 {
+		return dst, fmt.Errorf("record has %d columns, want %d", len(in), 5)
+	}
+	if s := in[0]; s != "" {
+		x, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return dst, fmt.Errorf("column %d: %w", 0, err)
+		}
+		dst.ID = x
+	}
+	if s := in[1]; s != "" {
+		dst.Symbol = s
+	}
+	if s := in[2]; s != "" {
+		x, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return dst, fmt.Errorf("column %d: %w", 2, err)
+		}
+		dst.Price = x
+	}
+	if s := in[3]; s != "" {
+		n, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return dst, fmt.Errorf("column %d: %w", 3, err)
+		}
+		x := uint32(n)
+		dst.Quantity = &x
+	}
+	if s := in[4]; s != "" {
+		var x time.Time
+		if err := x.UnmarshalText([]byte(s)); err != nil {
+			return dst, fmt.Errorf("column %d: %w", 4, err)
+		}
+		dst.At = x
+	}
+	return dst, nil
+}
+
+// recordMapperImpl is the generated implementation of RecordMapper.
+type recordMapperImpl struct{}
+
+// NewRecordMapper returns a new RecordMapper implementation.
+func NewRecordMapper() RecordMapper { return &recordMapperImpl{} }
+
+// AccountFromRow maps values to the destination type.
+func (m *recordMapperImpl) AccountFromRow(values []any) (Account, error) {
+	return map_Slice_any_to_Account(values)
+}
+
+// ContactFromCSV maps record to the destination type.
+func (m *recordMapperImpl) ContactFromCSV(header []string, record []string) (*Contact, error) {
+	dst := new(Contact)
+	col := make(map[string]int, len(header))
+	for i, name := range header {
+		col[name] = i
+	}
+	for _, name := range []string{"name", "email", "age"} {
+		if _, ok := col[name]; !ok {
+			return dst, fmt.Errorf("column %q: not in header", name)
+		}
+	}
+	if len(record) < max(len(header), 1) {
+		return dst, fmt.Errorf("record has %d columns, want %d", len(record), max(len(header), 1))
+	}
+	if s := record[col["name"]]; s != "" {
+		dst.Name = s
+	}
+	if s := record[col["email"]]; s != "" {
+		dst.Email = s
+	}
+	if s := record[col["age"]]; s != "" {
+		x, err := strconv.Atoi(s)
+		if err != nil {
+			return dst, fmt.Errorf("column %q: %w", "age", err)
+		}
+		dst.Age = x
+	}
+	if s := record[0]; s != "" {
+		dst.Status = Status(s)
+	}
+	return dst, nil
+}
+
+// ContactFromNamedCSV maps col to the destination type.
+func (m *recordMapperImpl) ContactFromNamedCSV(headers []string, col []string) (Contact, error) {
+	var dst Contact
+	col1 := make(map[string]int, len(headers))
+	for i1, name1 := range headers {
+		col1[name1] = i1
+	}
+	for _, name1 := range []string{"name", "email", "age"} {
+		if _, ok := col1[name1]; !ok {
+			return dst, fmt.Errorf("column %q: not in header", name1)
+		}
+	}
+	if len(col) < max(len(headers), 1) {
+		return dst, fmt.Errorf("record has %d columns, want %d", len(col), max(len(headers), 1))
+	}
+	if s := col[col1["name"]]; s != "" {
+		dst.Name = s
+	}
+	if s := col[col1["email"]]; s != "" {
+		dst.Email = s
+	}
+	if s := col[col1["age"]]; s != "" {
+		x, err := strconv.Atoi(s)
+		if err != nil {
+			return dst, fmt.Errorf("column %q: %w", "age", err)
+		}
+		dst.Age = x
+	}
+	if s := col[0]; s != "" {
+		dst.Status = Status(s)
+	}
+	return dst, nil
+}
+
+// TradeFromCSV maps record to the destination type.
+func (m *recordMapperImpl) TradeFromCSV(record []string) (Trade, error) {
+	return map_Slice_string_to_Trade(record)
+}
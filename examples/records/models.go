@@ -0,0 +1,46 @@
+package records
+
+import "time"
+
+//go:generate go run ../../cmd/graftgen -interface=RecordMapper -output=graft_gen.go
+
+type Status string
+
+// Trade is read from CSV records by column index.
+type Trade struct {
+	ID       int64     `col:"0"`
+	Symbol   string    `col:"1"`
+	Price    float64   `col:"2"`
+	Quantity *uint32   `col:"3"`
+	At       time.Time `col:"4"`
+	Note     string
+}
+
+// Contact is read from CSV records by header name.
+type Contact struct {
+	Name   string `col:"name"`
+	Email  string `col:"email"`
+	Age    int    `col:"age"`
+	Status Status `col:"0"`
+}
+
+// Account is read from []any SQL row values.
+type Account struct {
+	ID      int64     `col:"0"`
+	Owner   string    `col:"1"`
+	Balance *float64  `col:"2"`
+	Active  bool      `col:"3"`
+	Tier    int32     `col:"4"`
+	Logins  *uint16   `col:"5"`
+	Rate    float32   `col:"6"`
+	Status  Status    `col:"7"`
+	Opened  time.Time `col:"8"`
+}
+
+type RecordMapper interface {
+	TradeFromCSV(record []string) (Trade, error)
+	ContactFromCSV(header, record []string) (*Contact, error)
+	// ContactFromNamedCSV takes a record named like a generated local.
+	ContactFromNamedCSV(headers, col []string) (Contact, error)
+	AccountFromRow(values []any) (Account, error)
+}
@@ -0,0 +1,87 @@
+package records
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordMapper(t *testing.T) {
+	m := NewRecordMapper()
+
+	t.Run("columns bind by index", func(t *testing.T) {
+		out, err := m.TradeFromCSV([]string{"42", "ACME", "12.5", "100", "2024-05-01T10:00:00Z"})
+		require.NoError(t, err)
+		qty := uint32(100)
+		require.Equal(t, Trade{ID: 42, Symbol: "ACME", Price: 12.5, Quantity: &qty, At: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)}, out)
+	})
+
+	t.Run("empty columns stay zero", func(t *testing.T) {
+		out, err := m.TradeFromCSV([]string{"42", "ACME", "", "", ""})
+		require.NoError(t, err)
+		require.Equal(t, Trade{ID: 42, Symbol: "ACME"}, out)
+	})
+
+	t.Run("errors name the column", func(t *testing.T) {
+		_, err := m.TradeFromCSV([]string{"42", "ACME", "cheap", "", ""})
+		require.EqualError(t, err, `column 2: strconv.ParseFloat: parsing "cheap": invalid syntax`)
+
+		_, err = m.TradeFromCSV([]string{"42", "ACME"})
+		require.EqualError(t, err, "record has 2 columns, want 5")
+	})
+
+	t.Run("columns bind by header name", func(t *testing.T) {
+		header := []string{"status", "email", "name", "age"}
+		out, err := m.ContactFromCSV(header, []string{"active", "ada@example.com", "Ada", "36"})
+		require.NoError(t, err)
+		require.Equal(t, &Contact{Name: "Ada", Email: "ada@example.com", Age: 36, Status: "active"}, out)
+
+		_, err = m.ContactFromCSV(header, []string{"active", "ada@example.com", "Ada", "old"})
+		require.EqualError(t, err, `column "age": strconv.Atoi: parsing "old": invalid syntax`)
+
+		_, err = m.ContactFromCSV(header, []string{"active", "ada@example.com"})
+		require.EqualError(t, err, "record has 2 columns, want 4")
+
+		_, err = m.ContactFromCSV([]string{"status", "name", "age"}, []string{"active", "Ada", "36"})
+		require.EqualError(t, err, `column "email": not in header`)
+	})
+
+	t.Run("header locals do not shadow the record", func(t *testing.T) {
+		out, err := m.ContactFromNamedCSV([]string{"status", "email", "name", "age"}, []string{"active", "ada@example.com", "Ada", "36"})
+		require.NoError(t, err)
+		require.Equal(t, Contact{Name: "Ada", Email: "ada@example.com", Age: 36, Status: "active"}, out)
+	})
+
+	t.Run("row values convert from driver types", func(t *testing.T) {
+		balance := 9.5
+		logins := uint16(12)
+		opened := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+		out, err := m.AccountFromRow([]any{int64(1), []byte("Ada"), balance, true, int64(3), int64(12), float64(0.25), "active", opened})
+		require.NoError(t, err)
+		require.Equal(t, Account{ID: 1, Owner: "Ada", Balance: &balance, Active: true, Tier: 3, Logins: &logins, Rate: 0.25, Status: "active", Opened: opened}, out)
+
+		out, err = m.AccountFromRow([]any{int64(1), "Ada", nil, false, nil, nil, nil, []byte("closed"), nil})
+		require.NoError(t, err)
+		require.Equal(t, Account{ID: 1, Owner: "Ada", Status: "closed"}, out)
+	})
+
+	t.Run("row value errors name the column", func(t *testing.T) {
+		row := func(col int, v any) []any {
+			r := []any{int64(1), "Ada", nil, false, nil, nil, nil, nil, nil}
+			r[col] = v
+			return r
+		}
+		_, err := m.AccountFromRow(row(0, 1))
+		require.EqualError(t, err, "column 0: want int64, have int")
+
+		_, err = m.AccountFromRow(row(4, int64(1)<<40))
+		require.EqualError(t, err, "column 4: 1099511627776 overflows int32")
+
+		_, err = m.AccountFromRow(row(5, int64(-1)))
+		require.EqualError(t, err, "column 5: -1 overflows uint16")
+
+		_, err = m.AccountFromRow(row(7, 7))
+		require.EqualError(t, err, "column 7: want string or []byte, have int")
+	})
+}
@@ -0,0 +1,120 @@
+// Code generated by graftgen (version devel); DO NOT EDIT.
+
+// Source interfaces: CatalogMapper
+// Command: graftgen -interface=CatalogMapper -output=graft_gen.go
+
+package reshape
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// map_Catalog_to_CatalogDTO maps a value of type Catalog to CatalogDTO.
+func map_Catalog_to_CatalogDTO(in Catalog) (CatalogDTO, error) {
+	var dst CatalogDTO
+	if in.Items != nil {
+		dst.Items = make(map[string]ItemDTO, len(in.Items))
+		for _, v := range in.Items { // v used by child nodes
+			var key string
+			var mapped ItemDTO
+			key = string(v.ID)
+
+			mapped = map_Item_to_ItemDTO(v)
+
+			if _, dup := dst.Items[key]; dup {
+				return dst, fmt.Errorf("duplicate key %v mapping in.Items", key)
+			}
+			dst.Items[key] = mapped
+		}
+	} else {
+		dst.Items = nil
+	}
+	if in.Latest != nil {
+		dst.Latest = make(map[string]ItemDTO, len(in.Latest))
+		for _, v := range in.Latest { // v used by child nodes
+			var key string
+			var mapped ItemDTO
+			key = v.Name
+			mapped = map_Item_to_ItemDTO(v)
+
+			dst.Latest[key] = mapped
+		}
+	} else {
+		dst.Latest = nil
+	}
+	if in.ByVendor != nil {
+		dst.ByVendor = make(map[string]ItemDTO, len(in.ByVendor))
+		for _, v := range in.ByVendor { // v used by child nodes
+			if !(v != nil && v.Vendor != nil) {
+				continue
+			}
+			var key string
+			var mapped ItemDTO
+			key = v.Vendor.Code
+			mapped = map_Ptr_Item_to_ItemDTO(v)
+
+			if _, dup := dst.ByVendor[key]; !dup {
+				dst.ByVendor[key] = mapped
+			}
+		}
+	} else {
+		dst.ByVendor = nil
+	}
+	if in.Stock != nil {
+		dst.Stock = make([]ItemDTO, 0, len(in.Stock))
+		for _, k := range slices.Sorted(maps.Keys(in.Stock)) {
+			v := in.Stock[k] // v used by child nodes
+			var mapped ItemDTO
+			mapped = map_Item_to_ItemDTO(v)
+
+			dst.Stock = append(dst.Stock, mapped)
+		}
+	} else {
+		dst.Stock = nil
+	}
+	if in.Ranked != nil {
+		dst.Ranked = make([]ItemDTO, 0, len(in.Ranked))
+		for _, k := range slices.Backward(slices.Sorted(maps.Keys(in.Ranked))) {
+			v := in.Ranked[k] // v used by child nodes
+			var mapped ItemDTO
+			mapped = map_Item_to_ItemDTO(v)
+
+			dst.Ranked = append(dst.Ranked, mapped)
+		}
+	} else {
+		dst.Ranked = nil
+	}
+	return dst, nil
+}
+
+// map_Item_to_ItemDTO maps a value of type Item to ItemDTO.
+func map_Item_to_ItemDTO(in Item) ItemDTO {
+	var dst ItemDTO
+	dst.ID = in.ID
+	dst.Name = in.Name
+	return dst
+}
+
+// map_Ptr_Item_to_ItemDTO maps a value of type *Item to ItemDTO.
+func map_Ptr_Item_to_ItemDTO(in *Item) ItemDTO {
+	if in == nil {
+		return ItemDTO{}
+	}
+	var dst ItemDTO
+	dst.ID = in.ID
+	dst.Name = in.Name
+	return dst
+}
+
+// catalogMapperImpl is the generated implementation of CatalogMapper.
+type catalogMapperImpl struct{}
+
+// NewCatalogMapper returns a new CatalogMapper implementation.
+func NewCatalogMapper() CatalogMapper { return &catalogMapperImpl{} }
+
+// ToDTO maps c to the destination type.
+func (m *catalogMapperImpl) ToDTO(c Catalog) (CatalogDTO, error) {
+	return map_Catalog_to_CatalogDTO(c)
+}
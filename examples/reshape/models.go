@@ -0,0 +1,38 @@
+package reshape
+
+//go:generate go run ../../cmd/graftgen -interface=CatalogMapper -output=graft_gen.go
+
+type SKU string
+
+type Vendor struct{ Code string }
+
+type Item struct {
+	ID     SKU
+	Name   string
+	Vendor *Vendor
+}
+
+type ItemDTO struct {
+	ID   SKU
+	Name string
+}
+
+type Catalog struct {
+	Items    []Item
+	Latest   []Item
+	ByVendor []*Item
+	Stock    map[string]Item
+	Ranked   map[int]Item
+}
+
+type CatalogDTO struct {
+	Items    map[string]ItemDTO `mapkey:"ID"`
+	Latest   map[string]ItemDTO `mapkey:"Name" mapdup:"last"`
+	ByVendor map[string]ItemDTO `mapkey:"Vendor.Code" mapdup:"first"`
+	Stock    []ItemDTO
+	Ranked   []ItemDTO `maporder:"-key"`
+}
+
+type CatalogMapper interface {
+	ToDTO(c Catalog) (CatalogDTO, error)
+}
@@ -0,0 +1,39 @@
+package reshape
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReshape(t *testing.T) {
+	m := NewCatalogMapper()
+
+	t.Run("slices key into maps and maps order into slices", func(t *testing.T) {
+		out, err := m.ToDTO(Catalog{
+			Items:    []Item{{ID: "a", Name: "Apple"}, {ID: "b", Name: "Bean"}},
+			Latest:   []Item{{ID: "1", Name: "x"}, {ID: "2", Name: "x"}},
+			ByVendor: []*Item{{ID: "1", Vendor: &Vendor{Code: "v"}}, nil, {ID: "2"}, {ID: "3", Vendor: &Vendor{Code: "v"}}},
+			Stock:    map[string]Item{"b": {ID: "b"}, "a": {ID: "a"}, "c": {ID: "c"}},
+			Ranked:   map[int]Item{1: {ID: "one"}, 3: {ID: "three"}, 2: {ID: "two"}},
+		})
+		require.NoError(t, err)
+		require.Equal(t, map[string]ItemDTO{"a": {ID: "a", Name: "Apple"}, "b": {ID: "b", Name: "Bean"}}, out.Items)
+		require.Equal(t, map[string]ItemDTO{"x": {ID: "2", Name: "x"}}, out.Latest)
+		require.Equal(t, map[string]ItemDTO{"v": {ID: "1"}}, out.ByVendor)
+		require.Equal(t, []ItemDTO{{ID: "a"}, {ID: "b"}, {ID: "c"}}, out.Stock)
+		require.Equal(t, []ItemDTO{{ID: "three"}, {ID: "two"}, {ID: "one"}}, out.Ranked)
+	})
+
+	t.Run("duplicate keys fail by default", func(t *testing.T) {
+		_, err := m.ToDTO(Catalog{Items: []Item{{ID: "a"}, {ID: "a"}}})
+		require.ErrorContains(t, err, "duplicate key a")
+	})
+
+	t.Run("nil collections stay nil", func(t *testing.T) {
+		out, err := m.ToDTO(Catalog{})
+		require.NoError(t, err)
+		require.Nil(t, out.Items)
+		require.Nil(t, out.Stock)
+	})
+}
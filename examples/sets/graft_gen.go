@@ -0,0 +1,94 @@
+// Code generated by graftgen (version devel); DO NOT EDIT.
+
+// Source interfaces: AccessMapper
+// Command: graftgen -interface=AccessMapper -output=graft_gen.go
+
+package sets
+
+import "slices"
+
+// map_Role_to_RoleDTO maps a value of type Role to RoleDTO.
+func map_Role_to_RoleDTO(in Role) RoleDTO {
+	var dst RoleDTO
+	if in.Perms != nil {
+		dst.Perms = make([]string, 0, len(in.Perms))
+		for k := range in.Perms {
+			var mapped string
+			mapped = k.String()
+			dst.Perms = append(dst.Perms, mapped)
+		}
+		slices.Sort(dst.Perms)
+	} else {
+		dst.Perms = nil
+	}
+	if in.Flags != nil {
+		dst.Flags = make([]string, 0, len(in.Flags))
+		for k, member := range in.Flags {
+			if !member {
+				continue
+			}
+			var mapped string
+			mapped = k
+			dst.Flags = append(dst.Flags, mapped)
+		}
+		slices.Sort(dst.Flags)
+	} else {
+		dst.Flags = nil
+	}
+	if in.IDs != nil {
+		dst.IDs = make([]int64, 0, len(in.IDs))
+		for k := range in.IDs {
+			var mapped int64
+			mapped = int64(k)
+
+			dst.IDs = append(dst.IDs, mapped)
+		}
+		slices.Sort(dst.IDs)
+	} else {
+		dst.IDs = nil
+	}
+	return dst
+}
+
+// map_Grant_to_GrantModel maps a value of type Grant to GrantModel.
+func map_Grant_to_GrantModel(in Grant) GrantModel {
+	var dst GrantModel
+	if in.Perms != nil {
+		dst.Perms = make(map[Permission]struct{}, len(in.Perms))
+		for _, v := range in.Perms { // v used by child nodes
+			var key Permission
+			key = ParsePermission(v)
+
+			dst.Perms[key] = struct{}{}
+		}
+	} else {
+		dst.Perms = nil
+	}
+	if in.Flags != nil {
+		dst.Flags = make(map[string]bool, len(in.Flags))
+		for _, v := range in.Flags { // v used by child nodes
+			var key string
+			key = v
+			dst.Flags[key] = true
+		}
+	} else {
+		dst.Flags = nil
+	}
+	return dst
+}
+
+// accessMapperImpl is the generated implementation of AccessMapper.
+type accessMapperImpl struct{}
+
+// NewAccessMapper returns a new AccessMapper implementation.
+func NewAccessMapper() AccessMapper { return &accessMapperImpl{} }
+
+// ToDTO maps r to the destination type.
+func (m *accessMapperImpl) ToDTO(r Role) RoleDTO {
+	return map_Role_to_RoleDTO(r)
+}
+
+// ToModel maps g to the destination type.
+func (m *accessMapperImpl) ToModel(g Grant) GrantModel {
+	return map_Grant_to_GrantModel(g)
+}
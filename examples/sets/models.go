@@ -0,0 +1,62 @@
+package sets
+
+//go:generate go run ../../cmd/graftgen -interface=AccessMapper -output=graft_gen.go
+
+type Permission int
+
+const (
+	PermRead Permission = iota + 1
+	PermWrite
+	PermAdmin
+)
+
+func (p Permission) String() string {
+	switch p {
+	case PermRead:
+		return "read"
+	case PermWrite:
+		return "write"
+	case PermAdmin:
+		return "admin"
+	}
+	return "none"
+}
+
+// ParsePermission reads a permission name.
+func ParsePermission(s string) Permission {
+	for p := PermRead; p <= PermAdmin; p++ {
+		if p.String() == s {
+			return p
+		}
+	}
+	return 0
+}
+
+// Role stores its permissions and flags as sets.
+type Role struct {
+	Perms map[Permission]struct{}
+	Flags map[string]bool
+	IDs   map[int]struct{}
+}
+
+// RoleDTO lists the members of each set in sorted order.
+type RoleDTO struct {
+	Perms []string
+	Flags []string
+	IDs   []int64
+}
+
+type Grant struct {
+	Perms []string
+	Flags []string
+}
+
+type GrantModel struct {
+	Perms map[Permission]struct{}
+	Flags map[string]bool
+}
+
+type AccessMapper interface {
+	ToDTO(r Role) RoleDTO
+	ToModel(g Grant) GrantModel
+}
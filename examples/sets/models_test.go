@@ -0,0 +1,33 @@
+package sets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSets(t *testing.T) {
+	m := NewAccessMapper()
+
+	t.Run("sets become sorted slices", func(t *testing.T) {
+		out := m.ToDTO(Role{
+			Perms: map[Permission]struct{}{PermWrite: {}, PermAdmin: {}, PermRead: {}},
+			Flags: map[string]bool{"beta": true, "alpha": true, "off": false},
+			IDs:   map[int]struct{}{3: {}, 1: {}, 2: {}},
+		})
+		require.Equal(t, []string{"admin", "read", "write"}, out.Perms)
+		require.Equal(t, []string{"alpha", "beta"}, out.Flags)
+		require.Equal(t, []int64{1, 2, 3}, out.IDs)
+	})
+
+	t.Run("slices become deduplicated sets", func(t *testing.T) {
+		out := m.ToModel(Grant{Perms: []string{"read", "write", "read"}, Flags: []string{"beta", "beta"}})
+		require.Equal(t, map[Permission]struct{}{PermRead: {}, PermWrite: {}}, out.Perms)
+		require.Equal(t, map[string]bool{"beta": true}, out.Flags)
+	})
+
+	t.Run("nil stays nil", func(t *testing.T) {
+		require.Equal(t, RoleDTO{}, m.ToDTO(Role{}))
+		require.Equal(t, GrantModel{}, m.ToModel(Grant{}))
+	})
+}
@@ -0,0 +1,132 @@
+// Code generated by graftgen (version devel); DO NOT EDIT.
+
+// Source interfaces: PaymentMapper
+// Command: graftgen -interface=PaymentMapper -output=graft_gen.go
+
+package sumtypes
+
+import (
+	"fmt"
+	"github.com/calumari/graft/examples/sum_types/pb"
+)
+
+// map_pb_Payment_to_PaymentModel maps a value of type pb.Payment to PaymentModel.
+func map_pb_Payment_to_PaymentModel(in pb.Payment) (PaymentModel, error) {
+	var dst PaymentModel
+	dst.ID = in.ID
+	switch v := in.Method.(type) {
+	case nil:
+	case *pb.Payment_Card:
+		var mapped Card
+		mapped = map_Ptr_pb_Payment_Card_to_Card(v)
+
+		dst.Method = mapped
+	case *pb.Payment_Bank:
+		var mapped Bank
+		mapped = map_Ptr_pb_Payment_Bank_to_Bank(v)
+
+		dst.Method = mapped
+	case *pb.Payment_Wallet:
+		var mapped EWallet
+		mapped = map_Ptr_pb_Payment_Wallet_to_EWallet(v)
+
+		dst.Method = mapped
+	default:
+		return dst, fmt.Errorf("mapping Method: unexpected type %T", v)
+	}
+	return dst, nil
+}
+
+// map_PaymentModel_to_pb_Payment maps a value of type PaymentModel to pb.Payment.
+func map_PaymentModel_to_pb_Payment(in PaymentModel) (pb.Payment, error) {
+	var dst pb.Payment
+	dst.ID = in.ID
+	switch v := in.Method.(type) {
+	case nil:
+	case Card:
+		var mapped *pb.Payment_Card
+		mapped = map_Card_to_Ptr_pb_Payment_Card(v)
+
+		dst.Method = mapped
+	case Bank:
+		var mapped *pb.Payment_Bank
+		mapped = map_Bank_to_Ptr_pb_Payment_Bank(v)
+
+		dst.Method = mapped
+	case EWallet:
+		var mapped *pb.Payment_Wallet
+		mapped = map_EWallet_to_Ptr_pb_Payment_Wallet(v)
+
+		dst.Method = mapped
+	default:
+		return dst, fmt.Errorf("mapping Method: unexpected type %T", v)
+	}
+	return dst, nil
+}
+
+// map_Ptr_pb_Payment_Card_to_Card maps a value of type *pb.Payment_Card to Card.
+func map_Ptr_pb_Payment_Card_to_Card(in *pb.Payment_Card) Card {
+	if in == nil {
+		return Card{}
+	}
+	var dst Card
+	dst.Number = in.Number
+	return dst
+}
+
+// map_Ptr_pb_Payment_Bank_to_Bank maps a value of type *pb.Payment_Bank to Bank.
+func map_Ptr_pb_Payment_Bank_to_Bank(in *pb.Payment_Bank) Bank {
+	if in == nil {
+		return Bank{}
+	}
+	var dst Bank
+	dst.IBAN = in.IBAN
+	return dst
+}
+
+// map_Ptr_pb_Payment_Wallet_to_EWallet maps a value of type *pb.Payment_Wallet to EWallet.
+func map_Ptr_pb_Payment_Wallet_to_EWallet(in *pb.Payment_Wallet) EWallet {
+	if in == nil {
+		return EWallet{}
+	}
+	var dst EWallet
+	dst.Provider = in.Provider
+	return dst
+}
+
+// map_Card_to_Ptr_pb_Payment_Card maps a value of type Card to *pb.Payment_Card.
+func map_Card_to_Ptr_pb_Payment_Card(in Card) *pb.Payment_Card {
+	dst := new(pb.Payment_Card)
+	dst.Number = in.Number
+	return dst
+}
+
+// map_Bank_to_Ptr_pb_Payment_Bank maps a value of type Bank to *pb.Payment_Bank.
+func map_Bank_to_Ptr_pb_Payment_Bank(in Bank) *pb.Payment_Bank {
+	dst := new(pb.Payment_Bank)
+	dst.IBAN = in.IBAN
+	return dst
+}
+
+// map_EWallet_to_Ptr_pb_Payment_Wallet maps a value of type EWallet to *pb.Payment_Wallet.
+func map_EWallet_to_Ptr_pb_Payment_Wallet(in EWallet) *pb.Payment_Wallet {
+	dst := new(pb.Payment_Wallet)
+	dst.Provider = in.Provider
+	return dst
+}
+
+// paymentMapperImpl is the generated implementation of PaymentMapper.
+type paymentMapperImpl struct{}
+
+// NewPaymentMapper returns a new PaymentMapper implementation.
+func NewPaymentMapper() PaymentMapper { return &paymentMapperImpl{} }
+
+// ToModel maps p0 to the destination type.
+func (m *paymentMapperImpl) ToModel(p0 pb.Payment) (PaymentModel, error) {
+	return map_pb_Payment_to_PaymentModel(p0)
+}
+
+// ToWire maps p0 to the destination type.
+func (m *paymentMapperImpl) ToWire(p0 PaymentModel) (pb.Payment, error) {
+	return map_PaymentModel_to_pb_Payment(p0)
+}
@@ -0,0 +1,31 @@
+package sumtypes
+
+import "github.com/calumari/graft/examples/sum_types/pb"
+
+//go:generate go run ../../cmd/graftgen -interface=PaymentMapper -output=graft_gen.go
+
+// Domain sum type sealed by an unexported method; Card and Bank pair with the
+// wire variants of package pb by name.
+
+//graft:variants Card Bank EWallet=*pb.Payment_Wallet
+type Method interface{ method() }
+
+type Card struct{ Number string }
+
+type Bank struct{ IBAN string }
+
+type EWallet struct{ Provider string }
+
+func (Card) method()    {}
+func (Bank) method()    {}
+func (EWallet) method() {}
+
+type PaymentModel struct {
+	ID     string
+	Method Method `mapvariants:"*pb.Payment_Card,*pb.Payment_Bank,*pb.Payment_Wallet=EWallet"`
+}
+
+type PaymentMapper interface {
+	ToModel(pb.Payment) (PaymentModel, error)
+	ToWire(PaymentModel) (pb.Payment, error)
+}
@@ -0,0 +1,38 @@
+package sumtypes
+
+import (
+	"testing"
+
+	"github.com/calumari/graft/examples/sum_types/pb"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSumTypes(t *testing.T) {
+	m := NewPaymentMapper()
+
+	t.Run("maps oneof wrappers to domain variants", func(t *testing.T) {
+		out, err := m.ToModel(pb.Payment{ID: "p1", Method: &pb.Payment_Card{Number: "4242"}})
+		require.NoError(t, err)
+		require.Equal(t, PaymentModel{ID: "p1", Method: Card{Number: "4242"}}, out)
+
+		out, err = m.ToModel(pb.Payment{ID: "p2", Method: &pb.Payment_Wallet{Provider: "pay"}})
+		require.NoError(t, err)
+		require.Equal(t, PaymentModel{ID: "p2", Method: EWallet{Provider: "pay"}}, out)
+	})
+
+	t.Run("maps domain variants back to wrappers", func(t *testing.T) {
+		for _, method := range []Method{Card{Number: "1"}, Bank{IBAN: "DE1"}, EWallet{Provider: "pay"}} {
+			wire, err := m.ToWire(PaymentModel{ID: "p", Method: method})
+			require.NoError(t, err)
+			back, err := m.ToModel(wire)
+			require.NoError(t, err)
+			require.Equal(t, method, back.Method)
+		}
+	})
+
+	t.Run("nil stays nil", func(t *testing.T) {
+		out, err := m.ToWire(PaymentModel{ID: "p"})
+		require.NoError(t, err)
+		require.Nil(t, out.Method)
+	})
+}
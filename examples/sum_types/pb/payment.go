@@ -0,0 +1,20 @@
+// Package pb holds wire types in the shape protoc-gen-go emits for a oneof;
+// like generated code, it carries no graft directives.
+package pb
+
+type isPayment_Method interface{ isPayment_Method() }
+
+type Payment_Card struct{ Number string }
+
+type Payment_Bank struct{ IBAN string }
+
+type Payment_Wallet struct{ Provider string }
+
+func (*Payment_Card) isPayment_Method()   {}
+func (*Payment_Bank) isPayment_Method()   {}
+func (*Payment_Wallet) isPayment_Method() {}
+
+type Payment struct {
+	ID     string
+	Method isPayment_Method
+}
@@ -1,6 +1,12 @@
 package generator
 
-import "strings"
+import (
+	"fmt"
+	"go/types"
+	"slices"
+	"strconv"
+	"strings"
+)
 
 // analyzeHelperErrors consolidates: fixed-point helper error marking, node annotation,
 // and success return node adjustment.
@@ -97,10 +103,211 @@ func (g *generator) analyzeHelperErrors(interfaces *[]interfaceModel) {
 	for hi := range g.helperModels {
 		if g.helperModels[hi].HasError {
 			for ni := range g.helperModels[hi].Body {
-				if g.helperModels[hi].Body[ni].Kind == nodeKindReturn {
+				switch g.helperModels[hi].Body[ni].Kind {
+				case nodeKindReturn, nodeKindIfNilReturn:
 					g.helperModels[hi].Body[ni].WithError = true
 				}
 			}
 		}
 	}
 }
+
+// checkMethodErrors rejects methods without an error result whose
+// conversion can fail.
+func (g *generator) checkMethodErrors(interfaces []interfaceModel) error {
+	helperErr := map[string]bool{}
+	for _, h := range g.helperModels {
+		helperErr[h.Name] = h.HasError
+	}
+	for _, im := range interfaces {
+		for _, mm := range im.Methods {
+			if mm.HasError {
+				continue
+			}
+			for _, n := range mm.Body {
+				call, _, _ := strings.Cut(n.Expr, "(")
+				if n.Kind == nodeKindReturn && helperErr[call] || n.Kind != nodeKindReturn && hasErrorNode([]codeNode{n}) {
+					return fmt.Errorf("method %s: the conversion can fail; declare an error result", mm.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// assignTempNames gives every error-returning (or pointer) call node a result
+// temporary that is unique within its function body, so several such
+// assignments can share a scope ("tmp", "tmp1", ...).
+func assignTempNames(body []codeNode) {
+	n := 0
+	var walk func([]codeNode)
+	walk = func(nodes []codeNode) {
+		for i := range nodes {
+			switch nodes[i].Kind {
+			case nodeKindAssignHelper, nodeKindAssignMethod, nodeKindAssignFunc, nodeKindChainStep, nodeKindPtrStructMap, nodeKindPtrMethodMap, nodeKindPtrFuncMap, nodeKindAdaptCall, nodeKindReduce:
+				if !nodes[i].WithError && nodes[i].Kind != nodeKindPtrMethodMap && nodes[i].Kind != nodeKindPtrFuncMap && nodes[i].Kind != nodeKindReduce && nodes[i].Ref == "" {
+					break // pointer conversions always take the result's address, folds accumulate in it
+				}
+				nodes[i].Tmp = "tmp"
+				if n > 0 {
+					nodes[i].Tmp += strconv.Itoa(n)
+				}
+				n++
+			}
+			walk(nodes[i].Children)
+		}
+	}
+	walk(body)
+}
+
+// analyzeHelperEnv threads the call environment (the context and the
+// //graft:deps and //graft:base values) through generated code. Helpers that
+// transitively call context-taking converters or environment methods receive
+// the matching parameters, calls to them get their leading arguments, and
+// every node learns the expressions in scope: the helper parameters in
+// helpers; in methods the context parameter (or context.Background()) and
+// the implementation's fields. It reports whether generated code references
+// a context.
+func (g *generator) analyzeHelperEnv(interfaces []interfaceModel) (bool, error) {
+	index := map[string]*helperModel{}
+	for i := range g.helperModels {
+		index[g.helperModels[i].Name] = &g.helperModels[i]
+	}
+	// calledHelper returns the helper invoked by a node, if any.
+	calledHelper := func(n *codeNode) *helperModel {
+		switch n.Kind {
+		case nodeKindAssignHelper, nodeKindPtrStructMap:
+			return index[n.Helper]
+		case nodeKindReturn:
+			if idx := strings.Index(n.Expr, "("); idx > 0 {
+				return index[n.Expr[:idx]]
+			}
+		}
+		return nil
+	}
+	// takes reports whether a helper receives env value k ("ctx" for the
+	// context).
+	takes := func(h *helperModel, k string) bool {
+		if k == "ctx" {
+			return h.HasContext
+		}
+		for _, p := range h.Env {
+			if p.Name == k {
+				return true
+			}
+		}
+		return false
+	}
+	var needs func(nodes []codeNode, k string) bool
+	needs = func(nodes []codeNode, k string) bool {
+		for i := range nodes {
+			n := &nodes[i]
+			if (k == "ctx" && n.UseContext) || (k != "ctx" && n.OnEnv == k) || needs(n.Children, k) {
+				return true
+			}
+			if h := calledHelper(n); h != nil && takes(h, k) {
+				return true
+			}
+		}
+		return false
+	}
+
+	keys := []string{"ctx"}
+	for _, ev := range g.envs {
+		keys = append(keys, ev.name)
+	}
+	changed := true
+	for changed {
+		changed = false
+		for i := range g.helperModels {
+			h := &g.helperModels[i]
+			for _, k := range keys {
+				if takes(h, k) || !needs(h.Body, k) {
+					continue
+				}
+				if k == "ctx" {
+					h.HasContext = true
+				} else {
+					h.Env = append(h.Env, paramModel{Name: k})
+				}
+				changed = true
+			}
+		}
+	}
+	// order and type environment parameters as declared.
+	for i := range g.helperModels {
+		h := &g.helperModels[i]
+		if len(h.Env) == 0 {
+			continue
+		}
+		var env []paramModel
+		for _, ev := range g.envs {
+			if takes(h, ev.name) {
+				env = append(env, paramModel{Name: ev.name, Type: types.TypeString(ev.typ, g.qualifier)})
+			}
+		}
+		h.Env = env
+	}
+
+	used := false
+	var annotate func(nodes []codeNode, ctxName string, args, recvs map[string]string)
+	annotate = func(nodes []codeNode, ctxName string, args, recvs map[string]string) {
+		for i := range nodes {
+			n := &nodes[i]
+			if h := calledHelper(n); h != nil {
+				var env []string
+				if h.HasContext {
+					env = append(env, ctxName)
+					used = true
+				}
+				for _, p := range h.Env {
+					env = append(env, args[p.Name])
+				}
+				if len(env) > 0 {
+					if n.Kind == nodeKindReturn {
+						idx := strings.Index(n.Expr, "(")
+						n.Expr = n.Expr[:idx+1] + strings.Join(env, ", ") + ", " + n.Expr[idx+1:]
+					} else {
+						n.EnvArgs = strings.Join(env, ", ") + ", "
+					}
+				}
+			}
+			if n.OnEnv != "" {
+				n.Method = recvs[n.OnEnv] + "." + n.Method
+			}
+			used = used || n.UseContext
+			n.CtxName = ctxName
+			annotate(n.Children, ctxName, args, recvs)
+		}
+	}
+	helperEnv := map[string]string{}
+	for _, ev := range g.envs {
+		helperEnv[ev.name] = ev.name
+	}
+	for i := range g.helperModels {
+		annotate(g.helperModels[i].Body, "ctx", helperEnv, helperEnv)
+	}
+	for ii := range interfaces {
+		im := &interfaces[ii]
+		args, recvs := map[string]string{}, map[string]string{}
+		for _, ev := range g.envs {
+			if slices.Contains(im.envs, ev.name) {
+				args[ev.name], recvs[ev.name] = ev.arg, ev.recv
+			}
+		}
+		for mi := range im.Methods {
+			mm := &im.Methods[mi]
+			for _, ev := range g.envs {
+				if _, ok := args[ev.name]; !ok && needs(mm.Body, ev.name) {
+					return false, fmt.Errorf("method %s.%s: needs the //graft:%s value %s, which the interface does not declare", im.Name, mm.Name, ev.name, types.TypeString(ev.typ, g.qualifier))
+				}
+			}
+			ctxName := mm.CtxParam
+			if ctxName == "" {
+				ctxName = "context.Background()"
+			}
+			annotate(mm.Body, ctxName, args, recvs)
+		}
+	}
+	return used, nil
+}
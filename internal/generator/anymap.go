@@ -0,0 +1,185 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// isAnyMap reports whether t is a map[string]any (possibly named).
+func isAnyMap(t types.Type) bool {
+	m, ok := t.Underlying().(*types.Map)
+	if !ok {
+		return false
+	}
+	k, ok := m.Key().Underlying().(*types.Basic)
+	return ok && k.Kind() == types.String && types.IsInterface(m.Elem()) && m.Elem().Underlying().(*types.Interface).Empty()
+}
+
+// anyMapPair reports whether srcType and destType convert between a struct
+// (or pointer to one) and a map[string]any.
+func anyMapPair(srcType, destType types.Type) bool {
+	ss, _ := underlyingStruct(srcType)
+	ds, _ := underlyingStruct(destType)
+	return (ss != nil && isAnyMap(destType)) || (isAnyMap(srcType) && ds != nil)
+}
+
+// anyMapType is the type nested structs are stored as in a map[string]any.
+var anyMapType = types.NewMap(types.Typ[types.String], types.Universe.Lookup("any").Type())
+
+// keyedPair reports whether srcType and destType convert between a struct and
+// a map keyed by field: a map[string]any in either direction, or url.Values
+// and the like to a struct; or read a positional record into a struct.
+func keyedPair(srcType, destType types.Type) bool {
+	return anyMapPair(srcType, destType) || valuesPair(srcType, destType) || recordPair(srcType, destType)
+}
+
+// ensureKeyedHelper returns the helper converting between a struct and a map
+// keyed by the keyTag tag of the struct fields (the field name when "").
+func (g *generator) ensureKeyedHelper(srcType, destType types.Type, keyTag string) string {
+	key := "keyed:" + types.TypeString(srcType, g.qualifier) + "->" + types.TypeString(destType, g.qualifier) + "#" + keyTag
+	if name, ok := g.helperNames[key]; ok {
+		return name
+	}
+	name := g.helperName(srcType, destType, false)
+	if keyTag != "" {
+		name += "_" + keyTag
+	}
+	g.helperNames[key] = name
+	g.helperPlans = append(g.helperPlans, helperPlan{name: name, srcType: srcType, destType: destType, keyed: true, keyTag: keyTag})
+	return name
+}
+
+// keyedNodes converts a struct to a keyed map or back through a helper;
+// reading a map may fail. It returns nil for other types.
+func (g *generator) keyedNodes(destExpr, srcExpr string, destType, srcType types.Type, opts assignOpts) []codeNode {
+	if !keyedPair(srcType, destType) {
+		return nil
+	}
+	helper := g.ensureKeyedHelper(srcType, destType, opts.keyTag)
+	return []codeNode{{Kind: nodeKindAssignHelper, Dest: destExpr, Src: srcExpr, Helper: helper, WithError: !isAnyMap(destType)}}
+}
+
+// keyedBody builds the body of a keyed helper, returning whether it can fail.
+func (g *generator) keyedBody(plan helperPlan) ([]codeNode, bool, error) {
+	if valuesPair(plan.srcType, plan.destType) {
+		return g.valuesBody(plan)
+	}
+	if recordPair(plan.srcType, plan.destType) {
+		return g.recordBody(plan)
+	}
+	return g.anyMapBody(plan)
+}
+
+// fieldKey returns the map key of a struct field under keyTag: the tag's
+// name (up to a comma, the field name when empty) and whether zero values
+// are omitted; skip is set for the "-" name.
+func fieldKey(s *types.Struct, i int, keyTag string) (key string, omitEmpty, skip bool) {
+	f := s.Field(i)
+	if keyTag == "" {
+		return f.Name(), false, false
+	}
+	tag, _ := reflect.StructTag(s.Tag(i)).Lookup(keyTag)
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "-" && opts == "" {
+		return "", false, true
+	}
+	if name == "" {
+		name = f.Name()
+	}
+	return name, strings.Contains(","+opts+",", ",omitempty,"), false
+}
+
+// nestedAnyMap reports whether a field of type t is stored as a nested
+// map[string]any: a struct (or pointer to one) of the mapped package with only
+// exported fields and no text or JSON encoding of its own. Other values, such
+// as time.Time, are stored as they are.
+func (g *generator) nestedAnyMap(t types.Type) bool {
+	if pt, ok := t.(*types.Pointer); ok {
+		t = pt.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok || named.Obj().Pkg() != g.pkg {
+		return false
+	}
+	s, ok := named.Underlying().(*types.Struct)
+	if !ok {
+		return false
+	}
+	for i := range s.NumFields() {
+		if !s.Field(i).Exported() {
+			return false
+		}
+	}
+	methods := types.NewMethodSet(types.NewPointer(named))
+	return methods.Lookup(nil, "MarshalText") == nil && methods.Lookup(nil, "MarshalJSON") == nil
+}
+
+// anyMapBody builds the body of a helper converting a struct to a
+// map[string]any (one entry per exported field, nested structs of the mapped
+// package stored as maps and their nil pointers left out) or a map[string]any to a struct (present,
+// non-nil entries are type-asserted to the field type, with an error naming
+// the key on mismatch).
+func (g *generator) anyMapBody(plan helperPlan) ([]codeNode, bool, error) {
+	var body []codeNode
+	if isAnyMap(plan.destType) {
+		s, _ := underlyingStruct(plan.srcType)
+		if _, ok := plan.srcType.(*types.Pointer); ok {
+			body = append(body, codeNode{Kind: nodeKindIfNilReturn, Var: "in", Zero: "nil"})
+		}
+		destStr := types.TypeString(plan.destType, g.qualifier)
+		body = append(body,
+			codeNode{Kind: nodeKindDestInit, Var: "dst", DestType: destStr},
+			codeNode{Kind: nodeKindAssignDirect, Dest: "dst", Src: fmt.Sprintf("make(%s, %d)", destStr, s.NumFields())},
+		)
+		for i := 0; i < s.NumFields(); i++ {
+			f := s.Field(i)
+			key, omitEmpty, skip := fieldKey(s, i, plan.keyTag)
+			if !f.Exported() || skip {
+				continue
+			}
+			dest, src := "dst["+strconv.Quote(key)+"]", "in."+f.Name()
+			nodes := []codeNode{{Kind: nodeKindAssignDirect, Dest: dest, Src: src}}
+			var conds []string
+			if _, ptr := underlyingStruct(f.Type()); g.nestedAnyMap(f.Type()) {
+				nodes = []codeNode{{Kind: nodeKindAssignHelper, Dest: dest, Src: src, Helper: g.ensureKeyedHelper(f.Type(), anyMapType, plan.keyTag)}}
+				if ptr {
+					conds = append(conds, src+" != nil")
+				}
+			}
+			if omitEmpty && len(conds) == 0 {
+				check, err := g.nonZeroCheck(src, f.Type())
+				if err != nil {
+					return nil, false, fmt.Errorf("helper %s: field %s: %w", plan.name, f.Name(), err)
+				}
+				conds = append(conds, check)
+			}
+			body = append(body, guardNodes(conds, nodes)...)
+		}
+		return append(body, codeNode{Kind: nodeKindReturn, Expr: "dst"}), false, nil
+	}
+
+	s, ptr := underlyingStruct(plan.destType)
+	if ptr {
+		body = append(body, codeNode{Kind: nodeKindDestInitAlloc, Var: "dst", UnderType: types.TypeString(plan.destType.(*types.Pointer).Elem(), g.qualifier)})
+	} else {
+		body = append(body, codeNode{Kind: nodeKindDestInit, Var: "dst", DestType: types.TypeString(plan.destType, g.qualifier)})
+	}
+	for i := 0; i < s.NumFields(); i++ {
+		f := s.Field(i)
+		key, _, skip := fieldKey(s, i, plan.keyTag)
+		if !f.Exported() || skip {
+			continue
+		}
+		n := codeNode{Kind: nodeKindAnyMapLoad, Dest: "dst." + f.Name(), Src: "in", Expr: strconv.Quote(key), CastType: types.TypeString(f.Type(), g.qualifier), WithError: true}
+		if g.nestedAnyMap(f.Type()) {
+			n.CastType = types.TypeString(anyMapType, g.qualifier)
+			n.Helper = g.ensureKeyedHelper(anyMapType, f.Type(), plan.keyTag)
+		}
+		body = append(body, n)
+	}
+	g.addImport("fmt", "fmt")
+	return append(body, codeNode{Kind: nodeKindReturn, Expr: "dst", WithError: true}), true, nil
+}
@@ -2,21 +2,26 @@ package generator
 
 import (
 	"fmt"
+	"go/ast"
 	"go/token"
 	"go/types"
+	"path/filepath"
 	"strconv"
+	"strings"
 
 	"golang.org/x/tools/go/packages"
 )
 
 // loadDir loads the Go package(s) for a directory.
-func loadDir(dir string) ([]*packages.Package, error) {
+func loadDir(dir string, extra ...string) ([]*packages.Package, error) {
 	cfg := &packages.Config{
 		Mode: packages.NeedName | packages.NeedSyntax | packages.NeedTypes | packages.NeedTypesInfo | packages.NeedDeps | packages.NeedFiles | packages.NeedCompiledGoFiles,
 		Dir:  dir,
 	}
 
-	pkgs, err := packages.Load(cfg, "./")
+	// extra packages are loaded into the same graph so that types shared
+	// with the mapped package are identical.
+	pkgs, err := packages.Load(cfg, append([]string{"./"}, extra...)...)
 	if err != nil {
 		return nil, err
 	}
@@ -32,6 +37,210 @@ func loadDir(dir string) ([]*packages.Package, error) {
 	return result, nil
 }
 
+// splitLoaded separates the package in dir from the additionally loaded
+// (converter) packages.
+func splitLoaded(pkgs []*packages.Package, dir string) (*packages.Package, []*packages.Package) {
+	var main *packages.Package
+	var rest []*packages.Package
+	for _, p := range pkgs {
+		if main == nil && len(p.GoFiles) > 0 && filepath.Dir(p.GoFiles[0]) == dir {
+			main = p
+			continue
+		}
+		rest = append(rest, p)
+	}
+	return main, rest
+}
+
+// directive is a //graft:<name> <args> comment attached to a declaration.
+type directive struct {
+	name string
+	args string
+}
+
+const directivePrefix = "//graft:"
+
+// collectDirectives indexes //graft: directives found in the doc comments of
+// package-level declarations and interface methods by the position of the
+// declared identifier (matching types.Object.Pos).
+func collectDirectives(files []*ast.File) map[token.Pos][]directive {
+	res := map[token.Pos][]directive{}
+	add := func(id *ast.Ident, groups ...*ast.CommentGroup) {
+		for _, cg := range groups {
+			if cg == nil {
+				continue
+			}
+			for _, c := range cg.List {
+				text, ok := strings.CutPrefix(c.Text, directivePrefix)
+				if !ok {
+					continue
+				}
+				name, args, _ := strings.Cut(strings.TrimSpace(text), " ")
+				res[id.Pos()] = append(res[id.Pos()], directive{name: name, args: strings.TrimSpace(args)})
+			}
+		}
+	}
+	for _, f := range files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.FuncDecl:
+				add(n.Name, n.Doc)
+			case *ast.GenDecl:
+				for _, spec := range n.Specs {
+					if ts, ok := spec.(*ast.TypeSpec); ok {
+						if len(n.Specs) == 1 {
+							add(ts.Name, n.Doc)
+						}
+						add(ts.Name, ts.Doc)
+					}
+				}
+			case *ast.InterfaceType:
+				for _, m := range n.Methods.List {
+					for _, id := range m.Names {
+						add(id, m.Doc, m.Comment)
+					}
+				}
+			}
+			return true
+		})
+	}
+	return res
+}
+
+// applyInterfaceDirectives applies the //graft: directives of a mapper
+// interface declaration to its model and returns the //graft:base type, if
+// any.
+func (g *generator) applyInterfaceDirectives(im *interfaceModel, obj types.Object) (types.Type, error) {
+	var base types.Type
+	for _, d := range g.directives[obj.Pos()] {
+		tv, err := types.Eval(g.fset, g.pkg, obj.Pos(), d.args)
+		if err != nil || !tv.IsType() {
+			return nil, fmt.Errorf("interface %s: //graft:%s expects a type, have %q", im.Name, d.name, d.args)
+		}
+		typeStr := types.TypeString(tv.Type, g.qualifier)
+		switch d.name {
+		case "deps":
+			ev := envValue{name: "deps", typ: tv.Type, recv: "m.deps", arg: "m.deps"}
+			if err := g.registerEnv(ev); err != nil {
+				return nil, fmt.Errorf("interface %s: %w", im.Name, err)
+			}
+			im.DepsType = typeStr
+			im.CtorParams = append(im.CtorParams, paramModel{Name: "deps", Type: typeStr})
+			im.CtorInits = append(im.CtorInits, "deps: deps")
+		case "base":
+			// the base is embedded, so its methods are promoted onto the
+			// implementation; helpers receive a pointer to it.
+			t, ptr := tv.Type, false
+			if pt, ok := t.(*types.Pointer); ok {
+				t, ptr = pt.Elem(), true
+			}
+			named, ok := t.(*types.Named)
+			if _, isStruct := t.Underlying().(*types.Struct); !ok || !isStruct {
+				return nil, fmt.Errorf("interface %s: //graft:base must name a struct type, have %s", im.Name, d.args)
+			}
+			field := named.Obj().Name()
+			ev := envValue{name: "base", typ: types.NewPointer(t), recv: "m", arg: "&m." + field}
+			if ptr {
+				ev.arg = "m." + field
+				im.CtorParams = append(im.CtorParams, paramModel{Name: "base", Type: typeStr})
+				im.CtorInits = append(im.CtorInits, field+": base")
+			}
+			if err := g.registerEnv(ev); err != nil {
+				return nil, fmt.Errorf("interface %s: %w", im.Name, err)
+			}
+			im.Base = typeStr
+			base = ev.typ
+		default:
+			return nil, fmt.Errorf("interface %s: unknown directive //graft:%s", im.Name, d.name)
+		}
+		im.envs = append(im.envs, d.name)
+	}
+	return base, nil
+}
+
+// registerEnv records a value threaded through generated helpers and
+// registers its exported converter methods, which take precedence over
+// package functions for the same conversion. Mappers generated together
+// share one value of each kind.
+func (g *generator) registerEnv(ev envValue) error {
+	for _, e := range g.envs {
+		if e.name != ev.name {
+			continue
+		}
+		if !types.Identical(e.typ, ev.typ) {
+			return fmt.Errorf("conflicting //graft:%s types %s and %s", ev.name, types.TypeString(e.typ, g.qualifier), types.TypeString(ev.typ, g.qualifier))
+		}
+		return nil
+	}
+	g.envs = append(g.envs, ev)
+	// the value is held in an addressable field or variable, so
+	// pointer-receiver methods are callable as well.
+	mt := ev.typ
+	if _, isPtr := mt.Underlying().(*types.Pointer); !isPtr && !types.IsInterface(mt) {
+		mt = types.NewPointer(mt)
+	}
+	ms := types.NewMethodSet(mt)
+	for i := 0; i < ms.Len(); i++ {
+		fn, ok := ms.At(i).Obj().(*types.Func)
+		if !ok || !fn.Exported() {
+			continue
+		}
+		sig := fn.Type().(*types.Signature)
+		if !isConverterSig(sig) {
+			continue
+		}
+		srcT := types.TypeString(sig.Params().At(sig.Params().Len()-1).Type(), g.qualifier)
+		destT := types.TypeString(sig.Results().At(0).Type(), g.qualifier)
+		g.register(customFuncKey(srcT, destT, sig.Results().Len() == 2), registryEntry{Name: fn.Name(), HasError: sig.Results().Len() == 2, Kind: regKindEnvMethod, HasContext: sig.Params().Len() == 2, Env: ev.name, Owner: ev.name}, fn.Pos())
+	}
+	return nil
+}
+
+// applyMethodDirectives records the //graft: directives of an interface method
+// on its plan.
+func (g *generator) applyMethodDirectives(mp *methodPlan, m *types.Func) error {
+	for _, d := range g.directives[m.Pos()] {
+		switch d.name {
+		case "expr":
+			field, expr, _ := strings.Cut(d.args, " ")
+			expr = strings.TrimSpace(expr)
+			if field == "" || expr == "" {
+				return fmt.Errorf("method %s: //graft:expr expects <Field> <expression>", m.Name())
+			}
+			destStruct, _ := underlyingStruct(mp.signature.Results().At(0).Type())
+			if !mp.structMapping || findMatchingSourceField(destStruct, field) == nil {
+				return fmt.Errorf("method %s: //graft:expr: no destination field %s", m.Name(), field)
+			}
+			if mp.fieldExprs == nil {
+				mp.fieldExprs = map[string]string{}
+			}
+			mp.fieldExprs[field] = expr
+		case "use":
+			if d.args == "" || !mp.structMapping {
+				return fmt.Errorf("method %s: //graft:use expects a converter name on a struct mapping", m.Name())
+			}
+			mp.qualifier = d.args
+		case "nil":
+			switch d.args {
+			case nilKeep, nilEmpty, nilNil:
+			default:
+				return fmt.Errorf("method %s: //graft:nil expects keep, empty or nil", m.Name())
+			}
+			mp.nilPolicy = d.args
+		case "keytag":
+			if d.args == "" {
+				return fmt.Errorf("method %s: //graft:keytag expects a struct tag name", m.Name())
+			}
+			mp.keyTag = d.args
+		case "named":
+			// registered with the method as converter.
+		default:
+			return fmt.Errorf("method %s: unknown directive //graft:%s", m.Name(), d.name)
+		}
+	}
+	return nil
+}
+
 // validateMethodSig enforces signature shape constraints.
 func validateMethodSig(m *types.Func, sig *types.Signature) error {
 	if sig.Params().Len() < 1 {
@@ -56,14 +265,10 @@ func (g *generator) buildParamModels(sig *types.Signature) (params []paramModel,
 	for pi := 0; pi < sig.Params().Len(); pi++ {
 		p := sig.Params().At(pi)
 		pname := p.Name()
-		if nt, ok := p.Type().(*types.Named); ok {
-			if obj := nt.Obj(); obj != nil && obj.Name() == "Context" {
-				if pkg := obj.Pkg(); pkg != nil && pkg.Path() == "context" {
-					ctxIdx = pi
-					if pname == "" { // default name
-						pname = "ctx"
-					}
-				}
+		if isContextType(p.Type()) {
+			ctxIdx = pi
+			if pname == "" { // default name
+				pname = "ctx"
 			}
 		}
 		if pi != ctxIdx { // non-context param
@@ -77,14 +282,14 @@ func (g *generator) buildParamModels(sig *types.Signature) (params []paramModel,
 			continue
 		}
 		if primaryIdx == -1 {
-			if s, _ := underlyingStruct(p.Type()); s != nil || isCollectionLike(p.Type()) {
+			if s, _ := underlyingStruct(p.Type()); s != nil || isCollectionLike(p.Type()) || types.IsInterface(p.Type()) || isValuesMap(p.Type()) {
 				primaryIdx = pi
 			}
 		}
 	}
 
 	if primaryIdx == -1 {
-		return nil, -1, -1, fmt.Errorf("no struct or collection parameter to map from")
+		return nil, -1, -1, fmt.Errorf("no struct, collection or interface parameter to map from")
 	}
 
 	return params, ctxIdx, primaryIdx, nil
@@ -94,6 +299,10 @@ func (g *generator) buildParamModels(sig *types.Signature) (params []paramModel,
 func (g *generator) buildInterfaceModel(name string, iface *types.Interface) (*interfaceModel, []*methodPlan, error) {
 	implName := lowerFirst(name) + "Impl"
 	im := &interfaceModel{Name: name, ImplName: implName}
+	base, err := g.applyInterfaceDirectives(im, g.pkg.Scope().Lookup(name))
+	if err != nil {
+		return nil, nil, err
+	}
 
 	var plans []*methodPlan
 	for i := 0; i < iface.NumMethods(); i++ {
@@ -102,76 +311,101 @@ func (g *generator) buildInterfaceModel(name string, iface *types.Interface) (*i
 		if !ok {
 			return nil, nil, fmt.Errorf("method %s: not a signature", m.Name())
 		}
+		// methods of the base are hand-written; they are promoted through
+		// the embedded base and registered with it.
+		if base != nil {
+			if obj, _, _ := types.LookupFieldOrMethod(base, true, g.pkg, m.Name()); obj != nil {
+				if fn, ok := obj.(*types.Func); !ok || !types.Identical(fn.Type(), sig) {
+					return nil, nil, fmt.Errorf("method %s: base %s declares %s with a different signature", m.Name(), im.Base, m.Name())
+				}
+				continue
+			}
+		}
 		if err := validateMethodSig(m, sig); err != nil {
 			return nil, nil, err
 		}
 
-		// register interface method (for nested helper references) unless custom func variant present
-		srcT := types.TypeString(sig.Params().At(0).Type(), g.qualifier)
-		destT := types.TypeString(sig.Results().At(0).Type(), g.qualifier)
-		key := srcT + "->" + destT
-		if _, ok := g.registry[key]; !ok && g.findCustomVariant(key) == nil {
-			g.registry[key] = registryEntry{Name: m.Name(), HasError: sig.Results().Len() == 2, Kind: regKindInterfaceMethod}
-		}
-
 		params, ctxIdx, primaryIdx, err := g.buildParamModels(sig)
 		if err != nil {
 			return nil, nil, fmt.Errorf("method %s: %v", m.Name(), err)
 		}
 
+		// register single-source methods (for nested helper references)
+		// unless a custom func variant is present
+		if isConverterSig(sig) && (ctxIdx == -1 || ctxIdx == 0) {
+			srcT := types.TypeString(sig.Params().At(primaryIdx).Type(), g.qualifier)
+			destT := types.TypeString(sig.Results().At(0).Type(), g.qualifier)
+			key := srcT + "->" + destT
+			if g.converterName(m.Pos()) != "" || g.findCustomVariant(key) == nil {
+				g.register(key, registryEntry{Name: m.Name(), HasError: sig.Results().Len() == 2, Kind: regKindInterfaceMethod, HasContext: ctxIdx == 0, Owner: name}, m.Pos())
+			}
+		}
+
+		// a record method may take the column header first.
+		headerIdx := -1
+		if ri := recordHeaderIndex(sig, primaryIdx, ctxIdx); ri >= 0 {
+			headerIdx, primaryIdx = primaryIdx, ri
+		}
+
 		srcType := sig.Params().At(primaryIdx).Type()
 		destType := sig.Results().At(0).Type()
 
 		srcStruct, _ := underlyingStruct(srcType)
-		destStruct, dptr := underlyingStruct(destType)
+		destStruct, _ := underlyingStruct(destType)
 
 		structMap := srcStruct != nil && destStruct != nil
-		composite := isCollectionLike(srcType) && isCollectionLike(destType)
+		// interface sources map through a type switch over their variants.
+		composite := (isCollectionLike(srcType) && isCollectionLike(destType)) || types.IsInterface(srcType) || keyedPair(srcType, destType)
 
 		if !structMap && !composite {
 			return nil, nil, fmt.Errorf("method %s: unsupported top-level mapping (%s -> %s)", m.Name(), srcType.String(), destType.String())
 		}
-		// For simple single-param struct/composite mapping, pre-plan helper shell
-		if structMap && len(params) == 1 {
-			g.ensureStructHelper(srcType, destType)
-		}
-		if composite {
-			g.ensureCompositeHelper(srcType, destType)
-		}
 		mp := &methodPlan{
 			name:             m.Name(),
 			signature:        sig,
+			pos:              m.Pos(),
 			params:           params,
 			primaryIndex:     primaryIdx,
+			headerIndex:      headerIdx,
 			ctxIndex:         ctxIdx,
 			hasError:         sig.Results().Len() == 2,
 			structMapping:    structMap,
 			compositeMapping: composite,
 			implName:         implName,
 		}
-		_ = dptr // currently unused at plan level
+		if err := g.applyMethodDirectives(mp, m); err != nil {
+			return nil, nil, err
+		}
+		// For simple single-param struct/composite mapping, pre-plan helper shell
+		if structMap && mp.delegates() {
+			g.ensureStructHelper(srcType, destType, "")
+		}
+		if composite && headerIdx < 0 {
+			g.ensureCompositeHelper(srcType, destType, mp.nilPolicy, mp.keyTag)
+		}
 		plans = append(plans, mp)
 	}
 
 	return im, plans, nil
 }
 
-// discoverCustomFuncs finds eligible custom mapping functions.
-func (g *generator) discoverCustomFuncs(pkg *packages.Package, allowlist []string) map[string]registryEntry {
+// registerCustomFuncs registers the eligible custom mapping functions in
+// scope. For converter packages qual is the package name used to call them;
+// the allowlist applies to the mapped package only. Conversions already
+// registered from elsewhere are kept.
+func (g *generator) registerCustomFuncs(scope *types.Scope, qual string, allowlist []string) {
 	allowed := map[string]bool{}
-	if len(allowlist) > 0 {
+	if len(allowlist) > 0 && qual == "" {
 		for _, n := range allowlist {
 			allowed[n] = true
 		}
 	}
 
-	res := map[string]registryEntry{}
-	scope := pkg.Types.Scope()
 	for _, name := range scope.Names() {
 		if !token.IsExported(name) {
 			continue
 		}
-		if len(allowlist) > 0 && !allowed[name] {
+		if len(allowed) > 0 && !allowed[name] {
 			continue
 		}
 
@@ -181,23 +415,32 @@ func (g *generator) discoverCustomFuncs(pkg *packages.Package, allowlist []strin
 			continue
 		}
 		sig, ok := fn.Type().(*types.Signature)
-		if !ok || sig.Params().Len() != 1 || sig.Results().Len() < 1 || sig.Results().Len() > 2 {
-			continue
-		}
-		if sig.Results().Len() == 2 && !isErrorType(sig.Results().At(1).Type()) {
+		if !ok || !isConverterSig(sig) {
 			continue
 		}
+		withCtx := sig.Params().Len() == 2
 
-		srcT := types.TypeString(sig.Params().At(0).Type(), g.qualifier)
+		srcT := types.TypeString(sig.Params().At(sig.Params().Len()-1).Type(), g.qualifier)
 		destT := types.TypeString(sig.Results().At(0).Type(), g.qualifier)
 
-		key := customFuncKey(srcT, destT, sig.Results().Len() == 2)
-		if _, exists := g.registry[srcT+"->"+destT]; exists {
-			continue
+		if qual != "" {
+			name = qual + "." + name
 		}
-
-		res[key] = registryEntry{Name: name, HasError: sig.Results().Len() == 2, Kind: regKindCustomFunc}
+		g.register(customFuncKey(srcT, destT, sig.Results().Len() == 2), registryEntry{Name: name, HasError: sig.Results().Len() == 2, Kind: regKindCustomFunc, HasContext: withCtx, Owner: qual}, fn.Pos())
 	}
+}
 
-	return res
+// isConverterSig reports whether sig converts a single value, optionally
+// taking a context first: func([ctx context.Context,] S) (D[, error]).
+func isConverterSig(sig *types.Signature) bool {
+	params, results := sig.Params(), sig.Results()
+	switch {
+	case sig.Variadic() || results.Len() < 1 || results.Len() > 2:
+		return false
+	case results.Len() == 2 && !isErrorType(results.At(1).Type()):
+		return false
+	case params.Len() == 2:
+		return isContextType(params.At(0).Type())
+	}
+	return params.Len() == 1
 }
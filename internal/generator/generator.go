@@ -1,9 +1,13 @@
 package generator
 
 import (
+	"cmp"
 	"fmt"
+	"go/token"
 	"go/types"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"unicode/utf8"
 )
@@ -11,6 +15,8 @@ import (
 // generator holds transient state while building models.
 type generator struct {
 	currentPkgName string
+	pkg            *types.Package // package being generated into
+	fset           *token.FileSet
 	// registry maps src->dest (and src->dest#err) to metadata for interface
 	// methods or custom funcs.
 	registry     map[string]registryEntry
@@ -18,21 +24,54 @@ type generator struct {
 	helperModels []helperModel
 	helperPlans  []helperPlan // planning data for two-pass population
 	resolver     *fieldResolver
+	directives   map[token.Pos][]directive // //graft: comments by declaration position
+	imports      map[string]string         // import path -> package name referenced by generated code
+	convertPkgs  []*types.Package          // additional converter packages (-convert_pkgs)
+	envs         []envValue                // //graft:deps and //graft:base values
+	named        map[string][]string       // //graft:named qualifier -> converter names
+	compose      int                       // max converters chained by composePath
+	nilPolicy    string                    // global collection nil policy (-nil)
+	loopDepth    int                       // collection loops enclosing the nodes being built
+}
+
+// envValue is a value threaded through generated helpers whose methods serve
+// as converters: the //graft:deps dependency or the //graft:base
+// implementation.
+type envValue struct {
+	name string     // helper parameter name and registry Env key
+	typ  types.Type // helper parameter type
+	recv string     // receiver of its methods within mapper methods
+	arg  string     // helper argument within mapper methods
+}
+
+// methodEnv returns the environment value declaring method fn ("" if none).
+func (g *generator) methodEnv(fn *types.Func) string {
+	for _, ev := range g.envs {
+		if obj, _, _ := types.LookupFieldOrMethod(ev.typ, true, g.pkg, fn.Name()); obj == fn {
+			return ev.name
+		}
+	}
+	return ""
 }
 
 // helperPlan stores planning metadata prior to IR helperModel population.
 type helperPlan struct {
-	name               string
-	srcType            types.Type
-	destType           types.Type
-	srcIsPtr           bool
-	destIsPtr          bool
-	underDestType      string
-	zeroReturn         string
-	customFuncName     string
-	customFuncHasError bool
-	populated          bool
-	composite          bool // true for top-level collection/map helpers
+	name                 string
+	srcType              types.Type
+	destType             types.Type
+	srcIsPtr             bool
+	destIsPtr            bool
+	underDestType        string
+	zeroReturn           string
+	customFuncName       string
+	customFuncHasError   bool
+	customFuncHasContext bool
+	customFuncEnv        string
+	populated            bool
+	composite            bool   // true for top-level collection/map helpers
+	nilPolicy            string // //graft:nil or mapnil policy the helper is specialised for
+	keyed                bool   // struct <-> map[string]any or url.Values helper
+	keyTag               string // keyed: struct tag naming the map keys
 }
 
 // methodPlan stores method signature and high-level mapping classification
@@ -40,13 +79,30 @@ type helperPlan struct {
 type methodPlan struct {
 	name             string
 	signature        *types.Signature
+	pos              token.Pos
 	params           []paramModel // ordered (excluding synthesized names?)
 	primaryIndex     int
+	headerIndex      int // record methods: column header parameter, or -1
 	ctxIndex         int
 	hasError         bool
 	structMapping    bool
 	compositeMapping bool
 	implName         string
+	fieldExprs       map[string]string // dest field -> //graft:expr expression
+	qualifier        string            // //graft:use converter qualifier
+	nilPolicy        string            // //graft:nil collection policy
+	keyTag           string            // //graft:keytag struct tag of map[string]any keys
+}
+
+// delegates reports whether the method body is a plain call to a shared
+// helper (a single source besides an optional context); per-method field
+// overrides force an inline body instead.
+func (mp *methodPlan) delegates() bool {
+	sources := len(mp.params)
+	if mp.ctxIndex >= 0 {
+		sources--
+	}
+	return sources == 1 && len(mp.fieldExprs) == 0 && mp.qualifier == "" && mp.nilPolicy == "" && mp.keyTag == ""
 }
 
 // Run executes the generation with the provided configuration.
@@ -56,20 +112,63 @@ func Run(cfg *Config) error { return newGenerator().run(*cfg) }
 func newGenerator() *generator {
 	g := &generator{
 		registry:    make(map[string]registryEntry),
+		named:       make(map[string][]string),
 		helperNames: make(map[string]string),
+		imports:     make(map[string]string),
 	}
 
 	g.resolver = &fieldResolver{g: g}
 	return g
 }
 
+// qualifier renders package-qualified type names and records the package as a
+// candidate import (unused ones are pruned after rendering).
 func (g *generator) qualifier(p *types.Package) string {
 	if p == nil || p.Name() == g.currentPkgName {
 		return ""
 	}
+	return g.addImport(p.Path(), p.Name())
+}
+
+// sourceQualifier renders type names as the mapped package's own files refer
+// to them, for type-checking tag expressions in their scope.
+func (g *generator) sourceQualifier(p *types.Package) string {
+	if p == g.pkg {
+		return ""
+	}
 	return p.Name()
 }
 
+// reservedImports are referenced by name in the templates, so other packages
+// of the same name are aliased.
+var reservedImports = map[string]bool{"context": true, "fmt": true, "maps": true, "slices": true, "strconv": true, "time": true}
+
+// addImport records an import of path and returns the name generated code
+// refers to it by: name, or name2, name3... if another import already uses it.
+func (g *generator) addImport(path, name string) string {
+	if alias, ok := g.imports[path]; ok {
+		return alias
+	}
+	alias := name
+	for i := 2; g.importNameTaken(path, alias); i++ {
+		alias = name + strconv.Itoa(i)
+	}
+	g.imports[path] = alias
+	return alias
+}
+
+func (g *generator) importNameTaken(path, name string) bool {
+	if reservedImports[name] && path != name {
+		return true
+	}
+	for _, n := range g.imports {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
 func lowerFirst(s string) string {
 	if s == "" {
 		return s
@@ -94,6 +193,16 @@ func isErrorType(t types.Type) bool {
 	return false
 }
 
+// isContextType reports whether t is context.Context.
+func isContextType(t types.Type) bool {
+	nt, ok := t.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := nt.Obj()
+	return obj != nil && obj.Name() == "Context" && obj.Pkg() != nil && obj.Pkg().Path() == "context"
+}
+
 func underlyingStruct(t types.Type) (*types.Struct, bool) {
 	switch tt := t.(type) {
 	case *types.Pointer:
@@ -115,6 +224,31 @@ func (g *generator) zeroValue(t types.Type) string {
 	return types.TypeString(t, g.qualifier) + "{}"
 }
 
+// nonZeroCheck returns a boolean expression that holds when expr (of type t)
+// is not the zero value. Slices, maps and strings count as zero when empty.
+func (g *generator) nonZeroCheck(expr string, t types.Type) (string, error) {
+	switch u := t.Underlying().(type) {
+	case *types.Basic:
+		switch {
+		case u.Info()&types.IsBoolean != 0:
+			return expr, nil
+		case u.Info()&types.IsString != 0:
+			return expr + ` != ""`, nil
+		case u.Info()&types.IsNumeric != 0:
+			return expr + " != 0", nil
+		}
+	case *types.Pointer, *types.Interface, *types.Chan, *types.Signature:
+		return expr + " != nil", nil
+	case *types.Slice, *types.Map:
+		return "len(" + expr + ") > 0", nil
+	case *types.Struct, *types.Array:
+		if types.Comparable(t) {
+			return expr + " != (" + types.TypeString(t, g.qualifier) + "{})", nil
+		}
+	}
+	return "", fmt.Errorf("cannot check %s (%s) for zero value", expr, types.TypeString(t, g.qualifier))
+}
+
 func isStructLike(t types.Type) bool {
 	_, ok := t.Underlying().(*types.Struct)
 	return ok
@@ -128,13 +262,6 @@ func isCollectionLike(t types.Type) bool {
 	return false
 }
 
-func prefixDest(destPtr bool) string {
-	if destPtr {
-		return "mapped."
-	}
-	return "dst."
-}
-
 // helperName derives a deterministic (readable) name. Format:
 // map_<Src>_to_<Dest>_<N> where Src/Dest are simplified type tokens.
 func (g *generator) helperName(srcType, destType types.Type, composite bool) string {
@@ -202,21 +329,36 @@ func findTaggedSourceField(src *types.Struct, destName string) *types.Var {
 	return nil
 }
 
+// parseTag parses a struct tag following reflect.StructTag conventions:
+// space separated key:"value" pairs with strconv-quoted values.
 func parseTag(tag string) map[string]string {
 	res := map[string]string{}
 	tag = strings.Trim(tag, "`")
-	for _, p := range strings.Split(tag, " ") {
-		p = strings.TrimSpace(p)
-		if p == "" || !strings.Contains(p, ":") {
-			continue
+	for {
+		tag = strings.TrimLeft(tag, " ")
+		i := strings.Index(tag, ":")
+		if i <= 0 || i+1 >= len(tag) || tag[i+1] != '"' {
+			return res
+		}
+		key := tag[:i]
+		tag = tag[i+1:]
+		j := 1
+		for j < len(tag) && tag[j] != '"' {
+			if tag[j] == '\\' {
+				j++
+			}
+			j++
 		}
-		kv := strings.SplitN(p, ":", 2)
-		k := kv[0]
-		v := strings.Trim(kv[1], "\"")
-		v = strings.Trim(v, "\"")
-		res[k] = v
+		if j >= len(tag) {
+			return res
+		}
+		v, err := strconv.Unquote(tag[:j+1])
+		if err != nil {
+			return res
+		}
+		res[key] = v
+		tag = tag[j+1:]
 	}
-	return res
 }
 
 // package-level tag cache (single-threaded generator run)
@@ -254,11 +396,236 @@ func customFuncKey(src, dest string, hasErr bool) string {
 // findCustomVariant returns a custom func variant (non-error or error) if
 // present for the base key.
 func (g *generator) findCustomVariant(base string) *registryEntry {
-	if e, ok := g.registry[base]; ok && e.Kind == regKindCustomFunc {
+	if e, ok := g.registry[base]; ok && e.Kind != regKindInterfaceMethod {
 		return &e
 	}
-	if e, ok := g.registry[base+"#err"]; ok && e.Kind == regKindCustomFunc {
+	if e, ok := g.registry[base+"#err"]; ok && e.Kind != regKindInterfaceMethod {
 		return &e
 	}
 	return nil
 }
+
+// converterName returns the //graft:named qualifier of the converter declared
+// at pos ("" if unnamed).
+func (g *generator) converterName(pos token.Pos) string {
+	for _, d := range g.directives[pos] {
+		if d.name == "named" {
+			return d.args
+		}
+	}
+	return ""
+}
+
+// register adds converter e under key (src->dest[#err]). Named converters are
+// registered under key@name and only used when their qualifier is selected.
+// A converter of the same rank already holding the key makes it ambiguous;
+// one of a lower rank is replaced.
+func (g *generator) register(key string, e registryEntry, pos token.Pos) {
+	if q := g.converterName(pos); q != "" {
+		key += "@" + q
+		g.named[q] = append(g.named[q], e.Name)
+	}
+	prev, ok := g.registry[key]
+	switch {
+	case !ok:
+	case converterRank(prev) == converterRank(e):
+		prev.Ambiguous = append(prev.Ambiguous, e.Name)
+		g.registry[key] = prev
+		return
+	case converterRank(prev) > converterRank(e):
+		return
+	}
+	g.registry[key] = e
+}
+
+// converterRank orders converters competing for a conversion: environment
+// methods, functions of the mapped package, of converter packages, and mapper
+// methods last.
+func converterRank(e registryEntry) int {
+	switch {
+	case e.Kind == regKindEnvMethod:
+		return 3
+	case e.Kind == regKindCustomFunc && e.Owner == "":
+		return 2
+	case e.Kind == regKindCustomFunc:
+		return 1
+	}
+	return 0
+}
+
+// lookupConverter returns the converter registered for key (src->dest),
+// preferring the one named qual. Interface methods are only usable from a
+// different method of a mapper. Ambiguous unqualified matches are an error.
+func (g *generator) lookupConverter(key, qual, currentMethod string) (registryEntry, bool, error) {
+	bases := []string{key}
+	if qual != "" {
+		bases = []string{key + "@" + qual, key}
+	}
+	usable := func(k string) (registryEntry, bool) {
+		e, ok := g.registry[k]
+		return e, ok && !(e.Kind == regKindInterfaceMethod && (currentMethod == "" || e.Name == currentMethod))
+	}
+	for _, b := range bases {
+		// the #err variant precedes the qualifier in the key.
+		errKey := key + "#err" + strings.TrimPrefix(b, key)
+		e, ok := usable(b)
+		ee, eok := usable(errKey)
+		switch {
+		case !ok && !eok:
+			continue
+		case ok && eok && converterRank(e) == converterRank(ee):
+			e.Ambiguous = append(append(e.Ambiguous, ee.Name), ee.Ambiguous...)
+		case !ok, eok && converterRank(ee) > converterRank(e):
+			e = ee
+		}
+		if len(e.Ambiguous) > 0 {
+			return e, false, fmt.Errorf("ambiguous conversion %s: %s; mark one with //graft:named and select it", key, strings.Join(append([]string{e.Name}, e.Ambiguous...), ", "))
+		}
+		return e, true, nil
+	}
+	return registryEntry{}, false, nil
+}
+
+// composePath finds the shortest chain of registered converters from src to
+// dest (type strings) of at most g.compose steps. Named converters are not
+// chained; an ambiguous step or several shortest chains are an error.
+func (g *generator) composePath(src, dest, currentMethod string) ([]registryEntry, error) {
+	type edge struct {
+		to  string
+		e   registryEntry
+		err error // ambiguous converter
+	}
+	bases := map[string]bool{}
+	for k := range g.registry {
+		if !strings.Contains(k, "@") {
+			bases[strings.TrimSuffix(k, "#err")] = true
+		}
+	}
+	keys := make([]string, 0, len(bases))
+	for k := range bases {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	edges := map[string][]edge{}
+	for _, k := range keys {
+		e, ok, err := g.lookupConverter(k, "", currentMethod)
+		if !ok && err == nil {
+			continue
+		}
+		from, to, _ := strings.Cut(k, "->")
+		edges[from] = append(edges[from], edge{to: to, e: e, err: err})
+	}
+
+	type path struct {
+		at    string
+		steps []registryEntry
+		err   error
+	}
+	seen := map[string]bool{src: true}
+	level := []path{{at: src}}
+	for depth := 0; depth < g.compose && len(level) > 0; depth++ {
+		var next, found []path
+		for _, p := range level {
+			for _, ed := range edges[p.at] {
+				if seen[ed.to] {
+					continue
+				}
+				np := path{at: ed.to, steps: append(append([]registryEntry(nil), p.steps...), ed.e), err: cmp.Or(p.err, ed.err)}
+				if ed.to == dest {
+					found = append(found, np)
+				}
+				next = append(next, np)
+			}
+		}
+		switch len(found) {
+		case 0:
+		case 1:
+			return found[0].steps, found[0].err
+		default:
+			chains := make([]string, len(found))
+			for i, p := range found {
+				names := make([]string, len(p.steps))
+				for j, s := range p.steps {
+					names[j] = s.Name
+				}
+				chains[i] = strings.Join(names, ",")
+			}
+			return nil, fmt.Errorf("ambiguous conversion %s->%s: chains %s; select one with mapfn", src, dest, strings.Join(chains, " | "))
+		}
+		for _, p := range next {
+			seen[p.at] = true
+		}
+		level = next
+	}
+	return nil, nil
+}
+
+// usesNamed reports whether nodes call a converter named qual.
+func (g *generator) usesNamed(nodes []codeNode, qual string) bool {
+	for _, n := range nodes {
+		for _, name := range g.named[qual] {
+			if strings.TrimPrefix(n.Method, "m.") == name {
+				return true
+			}
+		}
+		if g.usesNamed(n.Children, qual) {
+			return true
+		}
+	}
+	return false
+}
+
+// scopePos returns the position at which expressions about obj are
+// type-checked: its declaration within the mapped package, else package scope.
+func (g *generator) scopePos(obj types.Object) token.Pos {
+	if obj.Pkg() != g.pkg {
+		return token.NoPos
+	}
+	return obj.Pos()
+}
+
+// lookupFunc resolves a tag's Func (package function or environment method) or
+// pkg.Func (imported at pos) and returns it with the expression calling it.
+func (g *generator) lookupFunc(name string, pos token.Pos) (*types.Func, string, error) {
+	pkgName, fnName, qualified := strings.Cut(name, ".")
+	if !qualified {
+		if fn, ok := g.pkg.Scope().Lookup(name).(*types.Func); ok {
+			return fn, name, nil
+		}
+		for _, ev := range g.envs {
+			obj, _, _ := types.LookupFieldOrMethod(ev.typ, true, g.pkg, name)
+			if fn, ok := obj.(*types.Func); ok {
+				return fn, name, nil
+			}
+		}
+		return nil, "", fmt.Errorf("function %s not found", name)
+	}
+	var imported *types.Package
+	scope := g.pkg.Scope()
+	scopes := []*types.Scope{}
+	if s := scope.Innermost(pos); s != nil && s != scope && s != types.Universe {
+		scopes = append(scopes, s)
+	}
+	for i := 0; i < scope.NumChildren(); i++ {
+		scopes = append(scopes, scope.Child(i))
+	}
+	for _, s := range scopes {
+		if pn, ok := s.Lookup(pkgName).(*types.PkgName); ok {
+			imported = pn.Imported()
+			break
+		}
+	}
+	for _, cp := range g.convertPkgs {
+		if imported == nil && cp.Name() == pkgName {
+			imported = cp
+		}
+	}
+	if imported == nil {
+		return nil, "", fmt.Errorf("package %s not imported", pkgName)
+	}
+	fn, ok := imported.Scope().Lookup(fnName).(*types.Func)
+	if !ok || !fn.Exported() {
+		return nil, "", fmt.Errorf("function %s not found", name)
+	}
+	return fn, g.addImport(imported.Path(), pkgName) + "." + fnName, nil
+}
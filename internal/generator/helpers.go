@@ -1,108 +1,696 @@
 package generator
 
 import (
+	"cmp"
 	"fmt"
+	"go/token"
 	"go/types"
+	"strconv"
+	"strings"
 )
 
-// buildAssignmentNodes maps srcExpr->destExpr with type-driven logic and may
-// create helpers.
-func (g *generator) buildAssignmentNodes(destExpr, srcExpr string, destType, srcType types.Type, currentMethod string, useCtx bool) []codeNode {
-	if types.Identical(destType, srcType) {
-		return []codeNode{{Kind: nodeKindAssignDirect, Dest: destExpr, Src: srcExpr}}
-	}
-
-	if types.AssignableTo(srcType, destType) {
-		return []codeNode{{Kind: nodeKindAssignCast, Dest: destExpr, Src: srcExpr, CastType: types.TypeString(destType, g.qualifier)}}
-	}
+// assignOpts carries the settings of the field being mapped through
+// buildAssignmentNodes.
+type assignOpts struct {
+	method    string    // enclosing mapper method ("" in helpers)
+	qual      string    // preferred //graft:named qualifier
+	dup       string    // mapdup policy for colliding converted map keys
+	key       string    // mapkey path keying slice elements into a map
+	order     string    // maporder of map values turned into a slice
+	length    string    // maplen policy for arrays of a different length
+	nilPolicy string    // nil/empty collection policy (mapnil, //graft:nil)
+	keyTag    string    // struct tag naming map[string]any keys (//graft:keytag)
+	variants  string    // mapvariants types of an interface source
+	unknown   string    // mapunknown policy for other dynamic types
+	path      string    // source field path named by runtime errors
+	pos       token.Pos // field declaration resolving the names above
+	// filter returns the condition an element v must meet to be mapped
+	// (mapfilter); it applies to the outermost slice or map only.
+	filter func(v string) string
+}
 
-	if key := types.TypeString(srcType, g.qualifier) + "->" + types.TypeString(destType, g.qualifier); true {
-		if mi, ok := g.registry[key]; ok && mi.Name != currentMethod {
-			if mi.Kind == regKindCustomFunc || currentMethod != "" {
-				if mi.Kind == regKindCustomFunc {
-					return []codeNode{{Kind: nodeKindAssignFunc, Dest: destExpr, Method: mi.Name, Arg: srcExpr, WithError: mi.HasError, UseContext: useCtx}}
-				}
-				return []codeNode{{Kind: nodeKindAssignMethod, Dest: destExpr, Method: mi.Name, Arg: srcExpr, WithError: mi.HasError, UseContext: useCtx}}
-			}
+// buildAssignmentNodes maps srcExpr->destExpr with type-driven logic and may
+// create helpers. Converters named opts.qual are preferred at every level.
+func (g *generator) buildAssignmentNodes(destExpr, srcExpr string, destType, srcType types.Type, opts assignOpts) ([]codeNode, error) {
+	// a filtered collection is always mapped element by element.
+	filter := opts.filter
+	opts.filter = nil
+	if filter == nil {
+		if nodes, err := g.directNodes(destExpr, srcExpr, destType, srcType, opts); nodes != nil || err != nil {
+			return nodes, err
 		}
 	}
 
-	switch dt := destType.(type) {
+	switch dt := destType.Underlying().(type) {
 	case *types.Slice:
-		if st, ok := srcType.(*types.Slice); ok {
+		if st, ok := srcType.Underlying().(*types.Map); ok && setKind(st.Elem()) != "" && setKind(dt.Elem()) != "bool" {
+			loop := g.enterLoop()
+			nodes, err := g.setToSliceNodes(destExpr, srcExpr, destType, dt, st, loop, opts)
+			g.loopDepth--
+			if err != nil || nodes != nil {
+				return nodes, err
+			}
+		}
+		if st, ok := srcType.Underlying().(*types.Map); ok && setKind(st.Elem()) != "struct" {
+			loop := g.enterLoop()
+			nodes, err := g.mapToSliceNodes(destExpr, srcExpr, destType, dt, st, loop, opts)
+			g.loopDepth--
+			if err != nil || nodes != nil {
+				return nodes, err
+			}
+		}
+		if st, ok := srcType.Underlying().(*types.Array); ok {
+			loop := g.enterLoop()
+			child, err := g.buildAssignmentNodes(fmt.Sprintf("%s[i%s]", destExpr, loop), fmt.Sprintf("%s[i%s]", srcExpr, loop), dt.Elem(), st.Elem(), opts)
+			g.loopDepth--
+			if err != nil {
+				return nil, err
+			}
+			return []codeNode{{Kind: nodeKindArrayToSlice, Src: srcExpr, Dest: destExpr, DestType: types.TypeString(destType, g.qualifier), Children: child, LoopWithError: hasErrorNode(child), Loop: loop}}, nil
+		}
+		if st, ok := srcType.Underlying().(*types.Slice); ok {
 			delem, selem := dt.Elem(), st.Elem()
-			child := g.buildAssignmentNodes("mapped", "v", delem, selem, currentMethod, useCtx)
-			loopErr := false
-			for i := range child {
-				if child[i].WithError {
-					loopErr = true
-					break
-				}
+			loop := g.enterLoop()
+			child, err := g.buildAssignmentNodes("mapped"+loop, "v"+loop, delem, selem, opts)
+			g.loopDepth--
+			if err != nil {
+				return nil, err
+			}
+			n := codeNode{Kind: nodeKindSliceMap, Src: srcExpr, Dest: destExpr, Nil: g.collectionNil(opts), DestType: types.TypeString(destType, g.qualifier), ElemType: types.TypeString(delem, g.qualifier), Children: child, LoopWithError: hasErrorNode(child), Loop: loop}
+			if filter != nil {
+				n.Expr = filter("v" + loop)
 			}
-			return []codeNode{{Kind: nodeKindSliceMap, Src: srcExpr, Dest: destExpr, DestType: types.TypeString(destType, g.qualifier), ElemType: types.TypeString(delem, g.qualifier), Children: child, LoopWithError: loopErr}}
+			return []codeNode{n}, nil
 		}
 	case *types.Array:
-		if st, ok := srcType.(*types.Array); ok && dt.Len() == st.Len() {
-			delem, selem := dt.Elem(), st.Elem()
-			child := g.buildAssignmentNodes(fmt.Sprintf("%s[i]", destExpr), fmt.Sprintf("%s[i]", srcExpr), delem, selem, currentMethod, useCtx)
-			return []codeNode{{Kind: nodeKindArrayMap, Src: srcExpr, Dest: destExpr, Children: child}}
+		switch srcType.Underlying().(type) {
+		case *types.Array, *types.Slice:
+			loop := g.enterLoop()
+			nodes, err := g.arrayMapNodes(destExpr, srcExpr, destType, srcType, dt, loop, opts)
+			g.loopDepth--
+			return nodes, err
 		}
 	case *types.Map:
-		if st, ok := srcType.(*types.Map); ok && types.Identical(dt.Key(), st.Key()) {
-			dval, sval := dt.Elem(), st.Elem()
-			child := g.buildAssignmentNodes("mapped", "v", dval, sval, currentMethod, useCtx)
-			loopErr := false
-			for i := range child {
-				if child[i].WithError {
-					loopErr = true
-					break
-				}
+		if st, ok := srcType.Underlying().(*types.Slice); ok && opts.key == "" && setKind(dt.Elem()) != "" {
+			loop := g.enterLoop()
+			nodes, err := g.sliceToSetNodes(destExpr, srcExpr, destType, dt, st, loop, opts)
+			g.loopDepth--
+			if err != nil || nodes != nil {
+				return nodes, err
+			}
+		}
+		if st, ok := srcType.Underlying().(*types.Slice); ok && opts.key != "" {
+			loop := g.enterLoop()
+			nodes, err := g.sliceToMapNodes(destExpr, srcExpr, destType, dt, st, loop, opts)
+			g.loopDepth--
+			if err != nil || nodes != nil {
+				return nodes, err
+			}
+		}
+		if st, ok := srcType.Underlying().(*types.Map); ok {
+			loop := g.enterLoop()
+			nodes, err := g.mapMapNodes(destExpr, srcExpr, destType, dt, st, loop, filter, opts)
+			g.loopDepth--
+			if err != nil || nodes != nil {
+				return nodes, err
 			}
-			return []codeNode{{Kind: nodeKindMapMap, Src: srcExpr, Dest: destExpr, DestType: types.TypeString(destType, g.qualifier), ElemType: types.TypeString(dval, g.qualifier), Children: child, LoopWithError: loopErr}}
 		}
 	case *types.Pointer:
 		if st, ok := srcType.(*types.Pointer); ok && isStructLike(dt.Elem()) && isStructLike(st.Elem()) {
-			key := types.TypeString(st.Elem(), g.qualifier) + "->" + types.TypeString(dt.Elem(), g.qualifier)
-			if mi, ok := g.registry[key]; ok && mi.Name != currentMethod {
-				if mi.Kind == regKindCustomFunc || currentMethod != "" {
-					kind := nodeKindPtrMethodMap
-					if mi.Kind == regKindCustomFunc {
-						kind = nodeKindPtrFuncMap
-					}
-					return []codeNode{{Kind: kind, Src: srcExpr, Dest: destExpr, Method: mi.Name, WithError: mi.HasError, UseContext: useCtx}}
-				}
-			}
-			helper := g.ensureStructHelper(srcType, destType)
-			return []codeNode{{Kind: nodeKindPtrStructMap, Src: srcExpr, Dest: destExpr, Helper: helper, UseContext: useCtx}}
+			helper := g.ensureStructHelper(srcType, destType, opts.nilPolicy)
+			return []codeNode{{Kind: nodeKindPtrStructMap, Src: srcExpr, Dest: destExpr, Helper: helper}}, nil
 		}
 	}
 
-	if isStructLike(destType) && isStructLike(srcType) {
-		helper := g.ensureStructHelper(srcType, destType)
+	if nodes := g.keyedNodes(destExpr, srcExpr, destType, srcType, opts); nodes != nil {
+		return nodes, nil
+	}
+
+	if types.IsInterface(srcType) {
+		if nodes, err := g.typeSwitchNodes(destExpr, srcExpr, destType, srcType, opts); nodes != nil || err != nil {
+			return nodes, err
+		}
+	}
+
+	// struct values and pointers mix through a helper (a nil source maps to
+	// the zero value).
+	ss, _ := underlyingStruct(srcType)
+	ds, _ := underlyingStruct(destType)
+	if ss != nil && ds != nil {
+		helper := g.ensureStructHelper(srcType, destType, opts.nilPolicy)
 		withErr := false
 		// Inspect helperPlans for this helper to see if its custom func has error (quick heuristic).
-		keySrc := types.TypeString(srcType, g.qualifier)
-		keyDst := types.TypeString(destType, g.qualifier)
 		for _, hp := range g.helperPlans {
-			if types.TypeString(hp.srcType, g.qualifier) == keySrc && types.TypeString(hp.destType, g.qualifier) == keyDst {
+			if hp.name == helper {
 				if hp.customFuncName != "" && hp.customFuncHasError {
 					withErr = true
 				}
 				break
 			}
 		}
-		return []codeNode{{Kind: nodeKindAssignHelper, Dest: destExpr, Src: srcExpr, Helper: helper, UseContext: useCtx, WithError: withErr}}
+		return []codeNode{{Kind: nodeKindAssignHelper, Dest: destExpr, Src: srcExpr, Helper: helper, WithError: withErr}}, nil
+	}
+
+	// conversions nothing else covers may chain registered converters.
+	if g.compose > 0 {
+		steps, err := g.composePath(types.TypeString(srcType, g.qualifier), types.TypeString(destType, g.qualifier), opts.method)
+		if err != nil {
+			return nil, err
+		}
+		if len(steps) > 0 {
+			return composeNodes(destExpr, srcExpr, steps), nil
+		}
 	}
 
-	return []codeNode{{Kind: nodeKindUnsupported, SrcType: srcType.String(), DestType: destType.String()}}
+	return []codeNode{{Kind: nodeKindUnsupported, SrcType: srcType.String(), DestType: destType.String()}}, nil
 }
 
-func (g *generator) ensureStructHelper(srcType, destType types.Type) string {
+// directNodes maps srcExpr->destExpr as a whole: by assignment or a registered
+// converter (possibly across indirection). It returns nil when none applies.
+func (g *generator) directNodes(destExpr, srcExpr string, destType, srcType types.Type, opts assignOpts) ([]codeNode, error) {
+	// collections are copied element by element to apply a nil policy.
+	copied := g.collectionNil(opts) == "" || !isCollection(destType) || !isCollection(srcType)
+	if copied && types.Identical(destType, srcType) {
+		return []codeNode{{Kind: nodeKindAssignDirect, Dest: destExpr, Src: srcExpr}}, nil
+	}
+
+	if copied && types.AssignableTo(srcType, destType) {
+		return []codeNode{{Kind: nodeKindAssignCast, Dest: destExpr, Src: srcExpr, CastType: types.TypeString(destType, g.qualifier)}}, nil
+	}
+
 	key := types.TypeString(srcType, g.qualifier) + "->" + types.TypeString(destType, g.qualifier)
+	mi, ok, err := g.lookupConverter(key, opts.qual, opts.method)
+	switch {
+	case err != nil:
+		return nil, err
+	case !ok, !copied && mi.Kind == regKindInterfaceMethod:
+		// mapper methods follow their own nil policy.
+	case mi.HasError && mi.Kind != regKindInterfaceMethod && isStructLike(destType) && isStructLike(srcType):
+		// error-returning struct converters are wrapped by the struct helper.
+	case mi.Kind != regKindInterfaceMethod:
+		return []codeNode{{Kind: nodeKindAssignFunc, Dest: destExpr, Method: mi.Name, Arg: srcExpr, WithError: mi.HasError, UseContext: mi.HasContext, OnEnv: mi.Env}}, nil
+	default:
+		return []codeNode{{Kind: nodeKindAssignMethod, Dest: destExpr, Method: mi.Name, Arg: srcExpr, WithError: mi.HasError, UseContext: mi.HasContext}}, nil
+	}
+
+	return g.adaptNodes(destExpr, srcExpr, destType, srcType, opts)
+}
+
+// composeNodes emits a chain of registered converters as a funcChain node.
+func composeNodes(destExpr, srcExpr string, steps []registryEntry) []codeNode {
+	var children []codeNode
+	withErr := false
+	for _, s := range steps {
+		method := s.Name
+		if s.Kind == regKindInterfaceMethod {
+			method = "m." + method
+		}
+		children = append(children, codeNode{Kind: nodeKindChainStep, Method: method, WithError: s.HasError, UseContext: s.HasContext, OnEnv: s.Env})
+		withErr = withErr || s.HasError
+	}
+	return []codeNode{{Kind: nodeKindFuncChain, Dest: destExpr, Src: srcExpr, Children: children, WithError: withErr}}
+}
+
+// adaptNodes uses a registered converter whose source or result differs from
+// the field types by one level of indirection: a pointer source is
+// dereferenced under a nil guard (or a value source addressed), a value result
+// is addressed and a pointer result dereferenced unless nil. Converters
+// needing fewer adaptations are preferred.
+func (g *generator) adaptNodes(destExpr, srcExpr string, destType, srcType types.Type, opts assignOpts) ([]codeNode, error) {
+	type side struct {
+		typ types.Type
+		ref string // "*" (dereference) or "&" (address)
+	}
+	// variants lists t and its one-level (de)referenced form; ref converts
+	// between the two in the given direction.
+	variants := func(t types.Type, toVariant bool) []side {
+		deref, addr := "*", "&"
+		if !toVariant {
+			deref, addr = addr, deref
+		}
+		if pt, ok := t.(*types.Pointer); ok {
+			return []side{{t, ""}, {pt.Elem(), deref}}
+		}
+		return []side{{t, ""}, {types.NewPointer(t), addr}}
+	}
+	srcs, dests := variants(srcType, true), variants(destType, false)
+	for _, pair := range [][2]int{{0, 1}, {1, 0}, {1, 1}} {
+		cs, cd := srcs[pair[0]], dests[pair[1]]
+		key := types.TypeString(cs.typ, g.qualifier) + "->" + types.TypeString(cd.typ, g.qualifier)
+		mi, ok, err := g.lookupConverter(key, opts.qual, opts.method)
+		if err != nil || !ok {
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		method := mi.Name
+		if mi.Kind == regKindInterfaceMethod {
+			method = "m." + method
+		}
+		// pointer to pointer keeps the established nil-preserving shape.
+		if cs.ref == "*" && cd.ref == "&" {
+			kind := nodeKindPtrFuncMap
+			if mi.Kind == regKindInterfaceMethod {
+				kind, method = nodeKindPtrMethodMap, mi.Name
+			}
+			return []codeNode{{Kind: kind, Src: srcExpr, Dest: destExpr, Method: method, WithError: mi.HasError, UseContext: mi.HasContext, OnEnv: mi.Env}}, nil
+		}
+		n := codeNode{Kind: nodeKindAdaptCall, Dest: destExpr, Method: method, Arg: srcExpr, Ref: cd.ref, WithError: mi.HasError, UseContext: mi.HasContext, OnEnv: mi.Env}
+		if cs.ref != "" {
+			n.Arg = cs.ref + srcExpr
+		}
+		if cs.ref == "*" {
+			n.Var = srcExpr
+		}
+		return []codeNode{n}, nil
+	}
+	return nil, nil
+}
+
+// keyNodes converts a value to or from a map key like any other value, falling
+// back to the built-in basic conversions of scalarNodes.
+func (g *generator) keyNodes(destExpr, srcExpr string, destType, srcType types.Type, opts assignOpts) ([]codeNode, error) {
+	nodes, err := g.buildAssignmentNodes(destExpr, srcExpr, destType, srcType, opts)
+	if err != nil || !hasUnsupported(nodes) {
+		return nodes, err
+	}
+	if scalar := g.scalarNodes(destExpr, srcExpr, destType, srcType); scalar != nil {
+		return scalar, nil
+	}
+	return nodes, nil
+}
+
+// mapMapNodes maps a map value by value, converting keys of a different type
+// with keyNodes (nil when they cannot be converted). Keys converted by
+// anything but a cast or integer formatting may collide; opts.dup decides
+// whether that is an error (the default) or the first or last entry in
+// source key order wins.
+// Values failing a non-nil filter are skipped.
+func (g *generator) mapMapNodes(destExpr, srcExpr string, destType types.Type, dt, st *types.Map, loop string, filter func(string) string, opts assignOpts) ([]codeNode, error) {
+	n := codeNode{Kind: nodeKindMapMap, Src: srcExpr, Dest: destExpr, Nil: g.collectionNil(opts), DestType: types.TypeString(destType, g.qualifier), ElemType: types.TypeString(dt.Elem(), g.qualifier), Loop: loop}
+	if filter != nil {
+		n.Expr = filter("v" + loop)
+	}
+	if !types.Identical(dt.Key(), st.Key()) {
+		keys, err := g.keyNodes("key"+loop, "k"+loop, dt.Key(), st.Key(), opts)
+		if err != nil || hasUnsupported(keys) {
+			return nil, err
+		}
+		n.KeyType = types.TypeString(dt.Key(), g.qualifier)
+		n.Children = keys
+		if !injectiveKey(keys, "k"+loop) {
+			n.Dup = opts.dup
+			if n.Dup == "" {
+				n.Dup = mapDupError
+			}
+			if n.Dup == mapDupError {
+				g.addImport("fmt", "fmt")
+				n.WithError = true
+			} else {
+				// the first or last entry in source key order wins.
+				if !isOrdered(st.Key()) {
+					return nil, fmt.Errorf("mapdup %s: keys of type %s are not ordered", n.Dup, types.TypeString(st.Key(), g.qualifier))
+				}
+				g.addImport("maps", "maps")
+				g.addImport("slices", "slices")
+				n.Arg = "slices.Sorted(maps.Keys(" + srcExpr + "))"
+			}
+		}
+	}
+	child, err := g.buildAssignmentNodes("mapped"+loop, "v"+loop, dt.Elem(), st.Elem(), opts)
+	if err != nil {
+		return nil, err
+	}
+	n.Children = append(n.Children, child...)
+	n.LoopWithError = hasErrorNode(n.Children)
+	return []codeNode{n}, nil
+}
+
+// sliceToMapNodes keys the elements of a slice by the opts.key field path
+// (converted to the map key type) and maps them to the map values.
+// Elements whose path passes a nil pointer are skipped; duplicate keys are an
+// error unless opts.dup keeps the first or last element.
+func (g *generator) sliceToMapNodes(destExpr, srcExpr string, destType types.Type, dt *types.Map, st *types.Slice, loop string, opts assignOpts) ([]codeNode, error) {
+	elem := "v" + loop
+	path, ok := walkSourcePath(elem, st.Elem(), strings.Split(opts.key, "."))
+	if !ok {
+		return nil, fmt.Errorf("mapkey %s: no such field in %s", opts.key, types.TypeString(st.Elem(), g.qualifier))
+	}
+	guards := path.guards
+	if _, isPtr := st.Elem().Underlying().(*types.Pointer); isPtr {
+		guards = append([]string{elem + " != nil"}, guards...)
+	}
+	inner := opts
+	inner.key, inner.order = "", ""
+	keys, err := g.keyNodes("key"+loop, path.expr, dt.Key(), path.typ, inner)
+	if err != nil {
+		return nil, err
+	}
+	if hasUnsupported(keys) {
+		return nil, fmt.Errorf("mapkey %s: cannot convert %s to %s", opts.key, types.TypeString(path.typ, g.qualifier), types.TypeString(dt.Key(), g.qualifier))
+	}
+	child, err := g.buildAssignmentNodes("mapped"+loop, elem, dt.Elem(), st.Elem(), inner)
+	if err != nil {
+		return nil, err
+	}
+	n := codeNode{Kind: nodeKindSliceToMap, Src: srcExpr, Dest: destExpr, Nil: g.collectionNil(opts), DestType: types.TypeString(destType, g.qualifier), KeyType: types.TypeString(dt.Key(), g.qualifier), ElemType: types.TypeString(dt.Elem(), g.qualifier), Expr: strings.Join(guards, " && "), Children: append(keys, child...), Loop: loop, Dup: opts.dup}
+	if n.Dup == "" {
+		n.Dup = mapDupError
+	}
+	if n.Dup == mapDupError {
+		g.addImport("fmt", "fmt")
+		n.WithError = true
+	}
+	n.LoopWithError = hasErrorNode(n.Children)
+	return []codeNode{n}, nil
+}
+
+// mapToSliceNodes maps the values of a map to a slice ordered by key
+// (opts.order "key", the default, or "-key" for descending order). Maps with
+// unordered keys are only mapped with an explicit order, which fails.
+func (g *generator) mapToSliceNodes(destExpr, srcExpr string, destType types.Type, dt *types.Slice, st *types.Map, loop string, opts assignOpts) ([]codeNode, error) {
+	ordered := isOrdered(st.Key())
+	switch {
+	case opts.order != "" && opts.order != "key" && opts.order != "-key":
+		return nil, fmt.Errorf("maporder %q: want key or -key", opts.order)
+	case !ordered && opts.order != "":
+		return nil, fmt.Errorf("maporder %s: keys of type %s are not ordered", opts.order, types.TypeString(st.Key(), g.qualifier))
+	case !ordered:
+		return nil, nil
+	}
+	inner := opts
+	inner.key, inner.order = "", ""
+	child, err := g.buildAssignmentNodes("mapped"+loop, "v"+loop, dt.Elem(), st.Elem(), inner)
+	if err != nil {
+		return nil, err
+	}
+	g.addImport("maps", "maps")
+	g.addImport("slices", "slices")
+	keys := "slices.Sorted(maps.Keys(" + srcExpr + "))"
+	if opts.order == "-key" {
+		keys = "slices.Backward(" + keys + ")"
+	}
+	return []codeNode{{Kind: nodeKindMapToSlice, Src: srcExpr, Dest: destExpr, Nil: g.collectionNil(opts), DestType: types.TypeString(destType, g.qualifier), ElemType: types.TypeString(dt.Elem(), g.qualifier), Expr: keys, Children: child, LoopWithError: hasErrorNode(child), Loop: loop}}, nil
+}
+
+// setKind classifies the value type of a set-shaped map: "struct" for an
+// empty struct, "bool" for a boolean, "" otherwise.
+func setKind(t types.Type) string {
+	switch u := t.Underlying().(type) {
+	case *types.Struct:
+		if u.NumFields() == 0 {
+			return "struct"
+		}
+	case *types.Basic:
+		if u.Kind() == types.Bool {
+			return "bool"
+		}
+	}
+	return ""
+}
+
+// setToSliceNodes lists the members of a set (map[K]struct{}, or the true
+// entries of map[K]bool) as a slice of converted keys, sorted when the
+// element type is ordered or else in key order.
+func (g *generator) setToSliceNodes(destExpr, srcExpr string, destType types.Type, dt *types.Slice, st *types.Map, loop string, opts assignOpts) ([]codeNode, error) {
+	keys := ""
+	if !isOrdered(dt.Elem()) {
+		if !isOrdered(st.Key()) {
+			return nil, nil
+		}
+		g.addImport("maps", "maps")
+		keys = "slices.Sorted(maps.Keys(" + srcExpr + "))"
+	}
+	child, err := g.keyNodes("mapped"+loop, "k"+loop, dt.Elem(), st.Key(), opts)
+	if err != nil || hasUnsupported(child) {
+		return nil, err
+	}
+	g.addImport("slices", "slices")
+	return []codeNode{{Kind: nodeKindSetToSlice, Src: srcExpr, Dest: destExpr, Nil: g.collectionNil(opts), DestType: types.TypeString(destType, g.qualifier), ElemType: types.TypeString(dt.Elem(), g.qualifier), Expr: keys, Set: setKind(st.Elem()), Children: child, LoopWithError: hasErrorNode(child), Loop: loop}}, nil
+}
+
+// sliceToSetNodes collects the converted elements of a slice into a set
+// (map[K]struct{} or map[K]bool), dropping duplicates.
+func (g *generator) sliceToSetNodes(destExpr, srcExpr string, destType types.Type, dt *types.Map, st *types.Slice, loop string, opts assignOpts) ([]codeNode, error) {
+	keys, err := g.keyNodes("key"+loop, "v"+loop, dt.Key(), st.Elem(), opts)
+	if err != nil || hasUnsupported(keys) {
+		return nil, err
+	}
+	member := "true"
+	if setKind(dt.Elem()) == "struct" {
+		member = types.TypeString(dt.Elem(), g.qualifier) + "{}"
+	}
+	return []codeNode{{Kind: nodeKindSliceToSet, Src: srcExpr, Dest: destExpr, Nil: g.collectionNil(opts), DestType: types.TypeString(destType, g.qualifier), KeyType: types.TypeString(dt.Key(), g.qualifier), Expr: member, Children: keys, LoopWithError: hasErrorNode(keys), Loop: loop}}, nil
+}
+
+// isOrdered reports whether values of t support the < operator.
+func isOrdered(t types.Type) bool {
+	b, ok := t.Underlying().(*types.Basic)
+	return ok && b.Info()&types.IsOrdered != 0
+}
+
+// nil policies for collection sources: keep nil and empty as they are, map
+// nil to empty, or empty to nil.
+const (
+	nilKeep  = "keep"
+	nilEmpty = "empty"
+	nilNil   = "nil"
+)
+
+// collectionNil returns the collection nil policy in effect for opts ("" for
+// keep).
+func (g *generator) collectionNil(opts assignOpts) string {
+	if p := cmp.Or(opts.nilPolicy, g.nilPolicy); p != nilKeep {
+		return p
+	}
+	return ""
+}
+
+// isCollection reports whether t is a slice or map.
+func isCollection(t types.Type) bool {
+	switch t.Underlying().(type) {
+	case *types.Slice, *types.Map:
+		return true
+	}
+	return false
+}
+
+// maplen policies for sources not matching the destination array length.
+const (
+	mapLenError    = "error"
+	mapLenPad      = "pad"
+	mapLenTruncate = "truncate"
+)
+
+// arrayMapNodes maps an array or slice onto an array element by element.
+// opts.length decides how a source of another length is handled: "error"
+// (the default) requires equal lengths, "pad" leaves the elements past a
+// shorter source zero and "truncate" additionally drops the elements past
+// the destination length. Array lengths are checked during generation, slice
+// lengths when mapping (an empty slice always fits).
+func (g *generator) arrayMapNodes(destExpr, srcExpr string, destType, srcType types.Type, dt *types.Array, loop string, opts assignOpts) ([]codeNode, error) {
+	var selem types.Type
+	n := codeNode{Kind: nodeKindArrayMap, Src: srcExpr, Dest: destExpr, DestType: types.TypeString(destType, g.qualifier), Loop: loop}
+	policy := cmp.Or(opts.length, mapLenError)
+	switch st := srcType.Underlying().(type) {
+	case *types.Array:
+		selem = st.Elem()
+		switch {
+		case st.Len() == dt.Len():
+		case policy == mapLenError || (policy == mapLenPad && st.Len() > dt.Len()):
+			return nil, fmt.Errorf("cannot map %s to %s: lengths differ (see maplen)", types.TypeString(srcType, g.qualifier), n.DestType)
+		case st.Len() > dt.Len():
+			n.Len = strconv.FormatInt(dt.Len(), 10)
+		}
+	case *types.Slice:
+		selem = st.Elem()
+		switch policy {
+		case mapLenError:
+			// a nil or empty slice maps to the zero array.
+			n.Expr = fmt.Sprintf("len(%s) != 0 && len(%s) != %d", srcExpr, srcExpr, dt.Len())
+		case mapLenPad:
+			n.Expr = fmt.Sprintf("len(%s) > %d", srcExpr, dt.Len())
+		default:
+			n.Len = fmt.Sprintf("min(len(%s), %d)", srcExpr, dt.Len())
+		}
+		if n.Expr != "" {
+			g.addImport("fmt", "fmt")
+			n.WithError = true
+		}
+	}
+	child, err := g.buildAssignmentNodes(fmt.Sprintf("%s[i%s]", destExpr, loop), fmt.Sprintf("%s[i%s]", srcExpr, loop), dt.Elem(), selem, opts)
+	if err != nil {
+		return nil, err
+	}
+	n.Children = child
+	n.LoopWithError = hasErrorNode(child)
+	return []codeNode{n}, nil
+}
+
+// mapdup policies for colliding converted map keys.
+const (
+	mapDupError = "error"
+	mapDupFirst = "first"
+	mapDupLast  = "last"
+)
+
+// injectiveKey reports whether key conversion nodes map distinct keys to
+// distinct keys: a plain cast or integer formatting of src.
+func injectiveKey(nodes []codeNode, src string) bool {
+	if len(nodes) != 1 {
+		return false
+	}
+	switch n := nodes[0]; n.Kind {
+	case nodeKindAssignDirect, nodeKindAssignCast:
+		return n.Src == src
+	case nodeKindFuncChain:
+		switch n.Children[0].Method {
+		case "strconv.Itoa", "strconv.FormatInt", "strconv.FormatUint":
+			return true
+		}
+	}
+	return false
+}
+
+// scalarNodes converts basic map keys without a registered converter: types
+// with the same underlying type and widening numeric conversions are cast,
+// Stringer and integer values are formatted as strings, and strings are
+// parsed as (base 10) integers.
+func (g *generator) scalarNodes(destExpr, srcExpr string, destType, srcType types.Type) []codeNode {
+	db, ok := destType.Underlying().(*types.Basic)
+	if !ok {
+		return nil
+	}
+	dest := types.TypeString(destType, g.qualifier)
+	plainDest := types.Identical(destType, db)
+	// cast converts expr to the destination type unless it already has it.
+	cast := func(expr string, t types.Type) []codeNode {
+		if types.Identical(t, destType) {
+			return []codeNode{{Kind: nodeKindAssignDirect, Dest: destExpr, Src: expr}}
+		}
+		return []codeNode{{Kind: nodeKindAssignCast, Dest: destExpr, Src: expr, CastType: dest}}
+	}
+	// call converts through a strconv function taking extra trailing args.
+	call := func(fn, arg, args string, withErr bool, result types.Type) []codeNode {
+		g.addImport("strconv", "strconv")
+		n := codeNode{Kind: nodeKindFuncChain, Dest: destExpr, Src: arg, WithError: withErr, Children: []codeNode{{Kind: nodeKindChainStep, Method: "strconv." + fn, Arg: args, WithError: withErr}}}
+		if !types.Identical(result, destType) {
+			n.CastType = dest
+		}
+		return []codeNode{n}
+	}
+
+	sb, basic := srcType.Underlying().(*types.Basic)
+	switch {
+	case basic && (types.Identical(sb, db) || widens(sb, db)):
+		return cast(srcExpr, srcType)
+	case db.Info()&types.IsString != 0 && hasStringMethod(srcType):
+		return cast(srcExpr+".String()", types.Typ[types.String])
+	case !basic:
+	case db.Info()&types.IsString != 0 && sb.Info()&types.IsInteger != 0:
+		switch {
+		case types.Identical(srcType, types.Typ[types.Int]):
+			return call("Itoa", srcExpr, "", false, types.Typ[types.String])
+		case sb.Info()&types.IsUnsigned != 0:
+			return call("FormatUint", "uint64("+srcExpr+")", ", 10", false, types.Typ[types.String])
+		default:
+			return call("FormatInt", "int64("+srcExpr+")", ", 10", false, types.Typ[types.String])
+		}
+	case db.Info()&types.IsInteger != 0 && sb.Info()&types.IsString != 0:
+		arg := srcExpr
+		if !types.Identical(srcType, types.Typ[types.String]) {
+			arg = "string(" + srcExpr + ")"
+		}
+		if plainDest && db.Kind() == types.Int {
+			return call("Atoi", arg, "", true, types.Typ[types.Int])
+		}
+		bits := map[types.BasicKind]string{types.Int8: "8", types.Int16: "16", types.Int32: "32", types.Int64: "64", types.Uint8: "8", types.Uint16: "16", types.Uint32: "32", types.Uint64: "64"}[db.Kind()]
+		if bits == "" {
+			bits = "0"
+		}
+		if db.Info()&types.IsUnsigned != 0 {
+			return call("ParseUint", arg, ", 10, "+bits, true, types.Typ[types.Uint64])
+		}
+		return call("ParseInt", arg, ", 10, "+bits, true, types.Typ[types.Int64])
+	}
+	return nil
+}
+
+// widens reports whether converting from to to is a lossless numeric
+// conversion: to a larger integer of the same signedness, or float32 to
+// float64. int and uint hold 32 to 64 bits, so only int32/uint32 and smaller
+// widen to them and only int64/uint64 from them.
+func widens(from, to *types.Basic) bool {
+	bits := map[types.BasicKind]int{
+		types.Int8: 8, types.Int16: 16, types.Int32: 32, types.Int64: 64,
+		types.Uint8: 8, types.Uint16: 16, types.Uint32: 32, types.Uint64: 64,
+		types.Float32: 32, types.Float64: 64,
+	}
+	fb, tb := bits[from.Kind()], bits[to.Kind()]
+	switch from.Kind() {
+	case types.Int, types.Uint:
+		fb = 64
+	}
+	switch to.Kind() {
+	case types.Int, types.Uint:
+		tb = 32
+	}
+	const kind = types.IsInteger | types.IsUnsigned | types.IsFloat
+	return fb > 0 && tb >= fb && from.Info()&kind == to.Info()&kind
+}
+
+// hasStringMethod reports whether values of t have a String() string method.
+// Pointers and interfaces, which may be nil, are not formatted.
+func hasStringMethod(t types.Type) bool {
+	if _, isPtr := t.Underlying().(*types.Pointer); isPtr || types.IsInterface(t) {
+		return false
+	}
+	obj, _, _ := types.LookupFieldOrMethod(t, false, nil, "String")
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return false
+	}
+	sig := fn.Type().(*types.Signature)
+	return sig.Params().Len() == 0 && sig.Results().Len() == 1 && types.Identical(sig.Results().At(0).Type(), types.Typ[types.String])
+}
+
+// enterLoop opens a nested collection loop and returns the suffix of its
+// variables ("" for the outermost loop, then "1", "2", ...); callers
+// decrement g.loopDepth once the loop body is built.
+func (g *generator) enterLoop() string {
+	g.loopDepth++
+	if g.loopDepth == 1 {
+		return ""
+	}
+	return strconv.Itoa(g.loopDepth - 1)
+}
+
+// inLoop builds the nodes of a nested collection loop with build, passing it
+// the suffix of the loop variables.
+func (g *generator) inLoop(build func(loop string) ([]codeNode, error)) ([]codeNode, error) {
+	loop := g.enterLoop()
+	defer func() { g.loopDepth-- }()
+	return build(loop)
+}
+
+// ensureStructHelper returns the helper mapping struct srcType to destType,
+// specialised for a collection nil policy other than the global one.
+func (g *generator) ensureStructHelper(srcType, destType types.Type, nilPolicy string) string {
+	if nilPolicy == cmp.Or(g.nilPolicy, nilKeep) {
+		nilPolicy = ""
+	}
+	key := types.TypeString(srcType, g.qualifier) + "->" + types.TypeString(destType, g.qualifier)
+	if nilPolicy != "" {
+		key += "#" + nilPolicy
+	}
 	if name, ok := g.helperNames[key]; ok {
 		return name
 	}
 	name := g.helperName(srcType, destType, false)
+	if nilPolicy != "" {
+		name += "_" + nilPolicy
+	}
 	g.helperNames[key] = name
 
 	sStruct, sPtr := underlyingStruct(srcType)
@@ -130,42 +718,72 @@ func (g *generator) ensureStructHelper(srcType, destType types.Type) string {
 		customFuncHasError: false,
 		populated:          false,
 		composite:          false,
+		nilPolicy:          nilPolicy,
 	}
 	baseKey := types.TypeString(srcType, g.qualifier) + "->" + types.TypeString(destType, g.qualifier)
-	if mi, ok := g.registry[baseKey+"#err"]; ok && mi.Kind == regKindCustomFunc && mi.HasError {
+	plain, hasPlain := g.registry[baseKey]
+	if mi, ok := g.registry[baseKey+"#err"]; ok && mi.Kind != regKindInterfaceMethod && mi.HasError && len(mi.Ambiguous) == 0 && (!hasPlain || converterRank(mi) >= converterRank(plain)) {
 		plan.customFuncName = mi.Name
 		plan.customFuncHasError = true
+		plan.customFuncHasContext = mi.HasContext
+		plan.customFuncEnv = mi.Env
 	}
 	if plan.customFuncName == "" {
-		if mi, ok := g.registry[baseKey]; ok && mi.Kind == regKindCustomFunc {
+		if mi, ok := g.registry[baseKey]; ok && mi.Kind != regKindInterfaceMethod && len(mi.Ambiguous) == 0 {
 			plan.customFuncName = mi.Name
 			plan.customFuncHasError = false
+			plan.customFuncHasContext = mi.HasContext
+			plan.customFuncEnv = mi.Env
 		}
 	}
 	g.helperPlans = append(g.helperPlans, plan)
 	return name
 }
 
-func (g *generator) ensureCompositeHelper(srcType, destType types.Type) string {
+// ensureCompositeHelper returns the helper mapping a collection srcType to
+// destType under the given nil policy ("" for the global one), or between a
+// struct and a map keyed by keyTag.
+func (g *generator) ensureCompositeHelper(srcType, destType types.Type, nilPolicy, keyTag string) string {
+	if keyedPair(srcType, destType) {
+		return g.ensureKeyedHelper(srcType, destType, keyTag)
+	}
 	key := "comp:" + types.TypeString(srcType, g.qualifier) + "->" + types.TypeString(destType, g.qualifier)
+	if nilPolicy != "" {
+		key += "#" + nilPolicy
+	}
 	if name, ok := g.helperNames[key]; ok {
 		return name
 	}
 	name := g.helperName(srcType, destType, true)
+	if nilPolicy != "" {
+		name += "_" + nilPolicy
+	}
 	g.helperNames[key] = name
-	plan := helperPlan{name: name, srcType: srcType, destType: destType, composite: true}
+	plan := helperPlan{name: name, srcType: srcType, destType: destType, composite: true, nilPolicy: nilPolicy}
 	g.helperPlans = append(g.helperPlans, plan)
 	return name
 }
 
-func (g *generator) populateHelpers(scope *types.Scope) {
+func (g *generator) populateHelpers(scope *types.Scope) error {
 	for i := 0; i < len(g.helperPlans); i++ {
 		plan := g.helperPlans[i]
 		if plan.populated {
 			continue
 		}
+		if plan.keyed {
+			body, hasErr, err := g.keyedBody(plan)
+			if err != nil {
+				return err
+			}
+			g.helperModels = append(g.helperModels, helperModel{Name: plan.name, SrcType: types.TypeString(plan.srcType, g.qualifier), DestType: types.TypeString(plan.destType, g.qualifier), Body: body, HasError: hasErr})
+			plan.populated = true
+			continue
+		}
 		if plan.composite {
-			assignBody := g.buildAssignmentNodes("dst", "in", plan.destType, plan.srcType, "", false)
+			assignBody, err := g.buildAssignmentNodes("dst", "in", plan.destType, plan.srcType, assignOpts{nilPolicy: plan.nilPolicy})
+			if err != nil {
+				return err
+			}
 			hasErr := false
 			for i := range assignBody {
 				if assignBody[i].WithError || assignBody[i].LoopWithError {
@@ -196,7 +814,7 @@ func (g *generator) populateHelpers(scope *types.Scope) {
 				body = append(body, codeNode{Kind: nodeKindDestInit, Var: "dst", DestType: types.TypeString(plan.destType, g.qualifier)})
 			}
 			body = append(body,
-				codeNode{Kind: nodeKindAssignFunc, Dest: "dst", Method: plan.customFuncName, Arg: "in", WithError: plan.customFuncHasError},
+				codeNode{Kind: nodeKindAssignFunc, Dest: "dst", Method: plan.customFuncName, Arg: "in", WithError: plan.customFuncHasError, UseContext: plan.customFuncHasContext, OnEnv: plan.customFuncEnv},
 				codeNode{Kind: nodeKindReturn, Expr: "dst", WithError: plan.customFuncHasError},
 			)
 			mh := helperModel{
@@ -211,7 +829,10 @@ func (g *generator) populateHelpers(scope *types.Scope) {
 			plan.populated = true
 			continue
 		}
-		plans := g.resolver.helperStructPlans(plan, scope)
+		plans, err := g.resolver.helperStructPlans(plan, scope)
+		if err != nil {
+			return err
+		}
 		if plans == nil {
 			plan.populated = true
 			continue
@@ -247,4 +868,6 @@ func (g *generator) populateHelpers(scope *types.Scope) {
 		g.helperModels = append(g.helperModels, hm)
 		plan.populated = true
 	}
+
+	return nil
 }
@@ -14,12 +14,31 @@ const (
 	nodeKindAssignHelper  = "assignHelper"
 	nodeKindAssignMethod  = "assignMethod"
 	nodeKindAssignFunc    = "assignFunc"
+	nodeKindFuncChain     = "funcChain"
+	nodeKindChainStep     = "chainStep" // child of funcChain; rendered by node_funcChain
 	nodeKindSliceMap      = "sliceMap"
 	nodeKindArrayMap      = "arrayMap"
+	nodeKindArrayToSlice  = "arrayToSlice"
 	nodeKindMapMap        = "mapMap"
+	nodeKindSliceToMap    = "sliceToMap"
+	nodeKindMapToSlice    = "mapToSlice"
+	nodeKindSetToSlice    = "setToSlice"
+	nodeKindSliceToSet    = "sliceToSet"
+	nodeKindReduce        = "reduce" // mapagg fold into a temporary accumulator
 	nodeKindPtrStructMap  = "ptrStructMap"
 	nodeKindPtrMethodMap  = "ptrMethodMap"
 	nodeKindPtrFuncMap    = "ptrFuncMap"
+	nodeKindAdaptCall     = "adaptCall"    // converter call with pointer/value adaptation
+	nodeKindAnyMapLoad    = "anyMapLoad"   // type-asserted map[string]any entry
+	nodeKindValuesLoad    = "valuesLoad"   // parsed url.Values entry
+	nodeKindRecordHeader  = "recordHeader" // column indexes by header name
+	nodeKindRecordLen     = "recordLen"    // error for records shorter than Len
+	nodeKindRecordLoad    = "recordLoad"   // parsed []string column
+	nodeKindRecordAny     = "recordAny"    // []any column converted from a row value
+	nodeKindTypeSwitch    = "typeSwitch"
+	nodeKindTypeCase      = "typeCase" // child of typeSwitch; rendered by node_typeSwitch (ElemType: paired variant)
+	nodeKindCond          = "cond"
+	nodeKindBranch        = "branch" // child of cond; rendered by node_cond
 	nodeKindReturn        = "return"
 	nodeKindUnsupported   = "unsupported"
 )
@@ -30,6 +49,9 @@ type Config struct {
 	Interfaces  []string // interface type names to implement
 	Output      string   // output filename
 	CustomFuncs []string // optional: specific custom function names to consider (empty = discover all exported)
+	ConvertPkgs []string // optional: import paths whose exported converters are registered as well
+	Compose     int      // optional: max converters chained when no direct one exists (0 = off)
+	NilPolicy   string   // optional: nil/empty collection policy ("keep", "empty" or "nil"; "" = keep)
 	Debug       bool     // when true, inject template debug comments linking nodes to templates
 	Command     string   // full invocation command line
 	Version     string   // graftgen build version
@@ -37,21 +59,33 @@ type Config struct {
 
 // fileModel is the root template model for a generated file.
 type fileModel struct {
-	Package     string
-	Source      string
-	Helpers     []helperModel
-	Interfaces  []interfaceModel
-	NeedContext bool
-	Debug       bool
-	Command     string
-	Version     string
+	Package    string
+	Source     string
+	Imports    []importSpec
+	Helpers    []helperModel
+	Interfaces []interfaceModel
+	Debug      bool
+	Command    string
+	Version    string
+}
+
+// importSpec is a single import of the generated file; Name is set only when
+// the package name differs from the last path element.
+type importSpec struct {
+	Name string
+	Path string
 }
 
 // interfaceModel describes a single interface mapping plan.
 type interfaceModel struct {
-	Name     string
-	ImplName string
-	Methods  []methodModel
+	Name       string
+	ImplName   string
+	DepsType   string       // //graft:deps dependency held by the implementation
+	Base       string       // embedded //graft:base implementation
+	CtorParams []paramModel // constructor parameters
+	CtorInits  []string     // constructor field initializers
+	Methods    []methodModel
+	envs       []string // declared environment values
 }
 
 // methodModel captures a single interface method mapping plan.
@@ -62,7 +96,7 @@ type methodModel struct {
 	DestType     string
 	HasError     bool
 	Body         []codeNode
-	HasContext   bool
+	CtxParam     string // name of the context.Context parameter, if any
 }
 
 // paramModel is a lightweight view of a method parameter for templates.
@@ -80,6 +114,7 @@ type helperModel struct {
 	Body       []codeNode
 	HasError   bool
 	HasContext bool
+	Env        []paramModel // environment values taken after the context
 }
 
 // codeNode is an ir node used by templates to emit code fragments.
@@ -93,6 +128,7 @@ type codeNode struct {
 	Arg           string
 	Comment       string
 	Var           string
+	Tmp           string // result temporary for error-returning calls
 	UnderType     string // for pointer dest init alloc
 	Zero          string
 	WithError     bool
@@ -102,7 +138,20 @@ type codeNode struct {
 	Expr          string
 	Children      []codeNode
 	LoopWithError bool
-	UseContext    bool
+	UseContext    bool   // the call takes a context.Context first
+	CtxName       string // context expression in scope (set before rendering)
+	OnEnv         string // Method belongs to this environment value ("deps", "base")
+	EnvArgs       string // helper call: leading context/environment arguments (set before rendering)
+	Ref           string // adaptCall: "&" or "*" applied to the call result
+	Loop          string // collection loops: suffix of the loop variables (i, k, v, mapped)
+	KeyType       string // mapMap, sliceToMap: converted key type (key children come first)
+	Dup           string // mapMap, sliceToMap: mapdup policy for colliding keys
+	Set           string // setToSlice: set value kind ("struct" or "bool")
+	Len           string // arrayMap: loop bound when not the whole source; recordLen: columns read
+	Nil           string // collection nodes: nil policy other than keep ("empty", "nil")
+	Parse         string // valuesLoad, recordLoad: how values are parsed (parseCast, parseCall, parseText)
+	Label         string // valuesLoad, recordLoad, recordAny: error prefix format taking Expr ("key %q")
+	Check         string // recordAny: condition rejecting the row value n (out of range)
 	// debug fields
 	Debug bool
 	Path  string
@@ -114,7 +163,14 @@ type registryEntry struct {
 	Name     string
 	HasError bool
 	Kind     registryKind
-	// For functions we may need to know quickly if it was originally custom.
+	// HasContext marks functions taking a context.Context before the source.
+	HasContext bool
+	Env        string // environment value owning a regKindEnvMethod
+	// Owner identifies where the converter was declared (package qualifier,
+	// environment value or interface name); unnamed converters of the same
+	// rank competing for a key are ambiguous.
+	Owner     string
+	Ambiguous []string // further converters registered for the same key
 }
 
 type registryKind int
@@ -122,4 +178,5 @@ type registryKind int
 const (
 	regKindInterfaceMethod registryKind = iota
 	regKindCustomFunc
+	regKindEnvMethod // method of a //graft:deps or //graft:base value
 )
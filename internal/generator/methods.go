@@ -1,6 +1,7 @@
 package generator
 
 import (
+	"fmt"
 	"go/types"
 )
 
@@ -10,7 +11,6 @@ func (g *generator) buildMethodModelFromPlan(mp *methodPlan) (*methodModel, erro
 	sig := mp.signature
 	params := mp.params
 	ctxIndex := mp.ctxIndex
-	useCtx := ctxIndex >= 0 && ctxIndex < len(params)
 
 	primaryIdx := mp.primaryIndex
 	srcType := sig.Params().At(primaryIdx).Type()
@@ -23,12 +23,18 @@ func (g *generator) buildMethodModelFromPlan(mp *methodPlan) (*methodModel, erro
 
 	switch {
 	case mp.structMapping:
-		nodes, err = g.buildStructMethodNodes(mp, sig, params, ctxIndex, primaryName, srcType, destType, destStruct, destPtr, useCtx)
+		nodes, err = g.buildStructMethodNodes(mp, sig, params, ctxIndex, primaryName, srcType, destType, destStruct, destPtr)
 		if err != nil {
 			return nil, err
 		}
+	case mp.compositeMapping && mp.headerIndex >= 0:
+		nodes, err = g.recordNodes(primaryName, params[mp.headerIndex].Name, params, srcType, destType)
+		if err != nil {
+			return nil, fmt.Errorf("method %s: %w", mp.name, err)
+		}
+		nodes = append(nodes, codeNode{Kind: nodeKindReturn, Expr: "dst", WithError: mp.hasError})
 	case mp.compositeMapping:
-		helperName := g.ensureCompositeHelper(srcType, destType)
+		helperName := g.ensureCompositeHelper(srcType, destType, mp.nilPolicy, mp.keyTag)
 		callExpr := helperName + "(" + primaryName + ")"
 		nodes = []codeNode{{Kind: nodeKindReturn, Expr: callExpr, WithError: mp.hasError}}
 	}
@@ -42,17 +48,21 @@ func (g *generator) buildMethodModelFromPlan(mp *methodPlan) (*methodModel, erro
 		DestType:     destTypeStr,
 		HasError:     mp.hasError,
 		Body:         nodes,
-		HasContext:   useCtx,
+		CtxParam:     ctxParamName(params, ctxIndex),
 	}
 
 	return mm, nil
 }
 
 // buildStructMethodNodes returns IR nodes for a struct mapping method (single or multi param).
-func (g *generator) buildStructMethodNodes(mp *methodPlan, sig *types.Signature, params []paramModel, ctxIndex int, primaryName string, srcType, destType types.Type, destStruct *types.Struct, destPtr, useCtx bool) ([]codeNode, error) {
-	// Single-param: delegate directly to helper for clarity.
-	if len(params) == 1 {
-		helperName := g.ensureStructHelper(srcType, destType)
+func (g *generator) buildStructMethodNodes(mp *methodPlan, sig *types.Signature, params []paramModel, ctxIndex int, primaryName string, srcType, destType types.Type, destStruct *types.Struct, destPtr bool) ([]codeNode, error) {
+	// Single-param without overrides: delegate directly to helper for clarity.
+	if mp.delegates() {
+		key := types.TypeString(srcType, g.qualifier) + "->" + types.TypeString(destType, g.qualifier)
+		if _, _, err := g.lookupConverter(key, "", ""); err != nil {
+			return nil, fmt.Errorf("method %s: %w", mp.name, err)
+		}
+		helperName := g.ensureStructHelper(srcType, destType, "")
 		callExpr := helperName + "(" + primaryName + ")"
 		return []codeNode{{Kind: nodeKindReturn, Expr: callExpr, WithError: mp.hasError}}, nil
 	}
@@ -64,9 +74,9 @@ func (g *generator) buildStructMethodNodes(mp *methodPlan, sig *types.Signature,
 		nodes = append(nodes, codeNode{Kind: nodeKindIfNilReturn, Var: pn, Zero: g.zeroValue(destType), WithError: mp.hasError})
 	}
 
+	// the result is named dst either way, as error paths return it.
 	initVar := "dst"
 	if destPtr {
-		initVar = "mapped"
 		if pt, ok := destType.(*types.Pointer); ok {
 			under := types.TypeString(pt.Elem(), g.qualifier)
 			nodes = append(nodes, codeNode{Kind: nodeKindDestInitAlloc, Var: initVar, UnderType: under})
@@ -76,19 +86,30 @@ func (g *generator) buildStructMethodNodes(mp *methodPlan, sig *types.Signature,
 		nodes = append(nodes, codeNode{Kind: nodeKindDestInit, Var: initVar, DestType: types.TypeString(destType, g.qualifier)})
 	}
 
-	plans, err := g.resolver.methodStructPlans(mp, sig, destStruct, destPtr, params, ctxIndex, primaryName, useCtx)
+	plans, err := g.resolver.methodStructPlans(mp, sig, destStruct, params, ctxIndex, primaryName)
 	if err != nil {
 		return nil, err
 	}
 	for _, ap := range plans {
 		nodes = append(nodes, ap.Nodes...)
 	}
+	if mp.qualifier != "" && !g.usesNamed(nodes, mp.qualifier) {
+		return nil, fmt.Errorf("method %s: //graft:use %s: no field converts with a converter of that name", mp.name, mp.qualifier)
+	}
 
 	// Return pointer or value directly (pointer already allocated above).
 	nodes = append(nodes, codeNode{Kind: nodeKindReturn, Expr: initVar, WithError: mp.hasError})
 	return nodes, nil
 }
 
+// ctxParamName returns the name of the context parameter ("" when absent).
+func ctxParamName(params []paramModel, ctxIndex int) string {
+	if ctxIndex < 0 {
+		return ""
+	}
+	return params[ctxIndex].Name
+}
+
 // collectPtrParamNames returns names of struct params that are pointers (excluding context param).
 func (g *generator) collectPtrParamNames(sig *types.Signature, params []paramModel, ctxIndex int) []string {
 	var out []string
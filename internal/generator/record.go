@@ -0,0 +1,192 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// colTag is the struct tag binding a field to a record column: its index, or
+// its name in the header passed before the record.
+const colTag = "col"
+
+// recordElem returns the element type of a positional record ([]string or
+// []any), or nil for other types.
+func recordElem(t types.Type) types.Type {
+	s, ok := t.Underlying().(*types.Slice)
+	if !ok {
+		return nil
+	}
+	if b, ok := s.Elem().Underlying().(*types.Basic); ok && b.Kind() == types.String {
+		return s.Elem()
+	}
+	if i, ok := s.Elem().Underlying().(*types.Interface); ok && i.Empty() {
+		return s.Elem()
+	}
+	return nil
+}
+
+// hasColumns reports whether t is a struct (or pointer to one) with fields
+// bound to record columns.
+func hasColumns(t types.Type) bool {
+	s, _ := underlyingStruct(t)
+	if s == nil {
+		return false
+	}
+	for i := 0; i < s.NumFields(); i++ {
+		if _, ok := reflect.StructTag(s.Tag(i)).Lookup(colTag); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// recordPair reports whether srcType is a positional record read into the
+// struct destType by its col tags.
+func recordPair(srcType, destType types.Type) bool {
+	return recordElem(srcType) != nil && hasColumns(destType)
+}
+
+// headerParams are the parameter names marking a column header passed before
+// the record.
+var headerParams = []string{"header", "headers"}
+
+// recordHeaderIndex returns the index of the record parameter following the
+// header parameter at primaryIdx, for methods like
+// FromCSV(header, record []string) (Row, error), or -1.
+func recordHeaderIndex(sig *types.Signature, primaryIdx, ctxIdx int) int {
+	destType := sig.Results().At(0).Type()
+	header := sig.Params().At(primaryIdx)
+	if !hasColumns(destType) || !slices.Contains(headerParams, header.Name()) || recordElem(header.Type()) == nil || types.IsInterface(recordElem(header.Type())) {
+		return -1
+	}
+	for pi := primaryIdx + 1; pi < sig.Params().Len(); pi++ {
+		if pi != ctxIdx && recordElem(sig.Params().At(pi).Type()) != nil {
+			return pi
+		}
+	}
+	return -1
+}
+
+// recordLocals returns the suffix of the locals indexing a header (col, i and
+// name) that keeps them from shadowing one of params.
+func recordLocals(params []paramModel) string {
+	for n := 0; ; n++ {
+		suffix := ""
+		if n > 0 {
+			suffix = strconv.Itoa(n)
+		}
+		if !slices.ContainsFunc(params, func(p paramModel) bool {
+			return p.Name == "col"+suffix || p.Name == "i"+suffix || p.Name == "name"+suffix
+		}) {
+			return suffix
+		}
+	}
+}
+
+// rowValue fills in how n converts a database/sql row value to t: integer
+// kinds from int64 (checking the range), floats from float64, strings from
+// string or []byte and booleans from bool. Other types are type-asserted.
+func (g *generator) rowValue(n *codeNode, t types.Type) {
+	n.Kind, n.CastType = nodeKindRecordAny, types.TypeString(t, g.qualifier)
+	b, ok := t.Underlying().(*types.Basic)
+	if !ok {
+		return
+	}
+	switch info := b.Info(); {
+	case info&types.IsString != 0:
+		n.SrcType = "string"
+		return
+	case info&types.IsUnsigned != 0:
+		n.SrcType, n.Check = "int64", fmt.Sprintf("n < 0 || int64(%s(n)) != n", n.CastType)
+	case info&types.IsInteger != 0:
+		n.SrcType = "int64"
+		if b.Kind() != types.Int64 {
+			n.Check = fmt.Sprintf("int64(%s(n)) != n", n.CastType)
+		}
+	case info&types.IsFloat != 0:
+		n.SrcType = "float64"
+	case info&types.IsBoolean != 0:
+		n.SrcType = "bool"
+	}
+	if n.SrcType == n.CastType {
+		n.SrcType = ""
+	}
+}
+
+// recordNodes reads the record src into dst by the col tags of destType:
+// string columns are parsed by the field type (empty ones leave the field
+// zero) and []any columns converted from row values (nil ones leave it zero).
+// Column names are looked up in header, with locals that do not shadow
+// params; without one only indexes are allowed. Records shorter than the
+// columns read, or a header without a named column, are errors.
+func (g *generator) recordNodes(src, header string, params []paramModel, srcType, destType types.Type) ([]codeNode, error) {
+	var body []codeNode
+	s, ptr := underlyingStruct(destType)
+	if ptr {
+		body = append(body, codeNode{Kind: nodeKindDestInitAlloc, Var: "dst", UnderType: types.TypeString(destType.(*types.Pointer).Elem(), g.qualifier)})
+	} else {
+		body = append(body, codeNode{Kind: nodeKindDestInit, Var: "dst", DestType: types.TypeString(destType, g.qualifier)})
+	}
+	elem := recordElem(srcType)
+	locals := recordLocals(params)
+	var loads []codeNode
+	var names []string
+	width := 0
+	for i := 0; i < s.NumFields(); i++ {
+		f := s.Field(i)
+		col, ok := reflect.StructTag(s.Tag(i)).Lookup(colTag)
+		if !ok || col == "-" {
+			continue
+		}
+		if !f.Exported() {
+			return nil, fmt.Errorf("field %s: col tag on an unexported field", f.Name())
+		}
+		n := codeNode{Kind: nodeKindRecordLoad, Dest: "dst." + f.Name(), Var: "s", WithError: true}
+		if idx, err := strconv.Atoi(col); err == nil && idx >= 0 {
+			n.Src, n.Label, n.Expr = fmt.Sprintf("%s[%d]", src, idx), "column %d", col
+			width = max(width, idx+1)
+		} else {
+			if header == "" {
+				return nil, fmt.Errorf("field %s: column %q needs a header parameter", f.Name(), col)
+			}
+			n.Src, n.Label, n.Expr = src+"[col"+locals+"["+strconv.Quote(col)+"]]", "column %q", strconv.Quote(col)
+			names = append(names, strconv.Quote(col))
+		}
+		t := f.Type()
+		if pt, ok := t.(*types.Pointer); ok {
+			n.Ref, t = "&", pt.Elem()
+		}
+		if types.IsInterface(elem) {
+			g.rowValue(&n, t)
+		} else if err := g.valueParse(&n, t); err != nil {
+			return nil, fmt.Errorf("field %s: %w", f.Name(), err)
+		}
+		loads = append(loads, n)
+	}
+	if len(names) > 0 {
+		body = append(body, codeNode{Kind: nodeKindRecordHeader, Src: header, Expr: strings.Join(names, ", "), Loop: locals, WithError: true})
+	}
+	switch {
+	case len(names) > 0 && width > 0:
+		body = append(body, codeNode{Kind: nodeKindRecordLen, Src: src, Len: fmt.Sprintf("max(len(%s), %d)", header, width), WithError: true})
+	case len(names) > 0:
+		body = append(body, codeNode{Kind: nodeKindRecordLen, Src: src, Len: "len(" + header + ")", WithError: true})
+	case width > 0:
+		body = append(body, codeNode{Kind: nodeKindRecordLen, Src: src, Len: strconv.Itoa(width), WithError: true})
+	}
+	g.addImport("fmt", "fmt")
+	return append(body, loads...), nil
+}
+
+// recordBody builds the body of a helper reading a record by column indexes.
+func (g *generator) recordBody(plan helperPlan) ([]codeNode, bool, error) {
+	body, err := g.recordNodes("in", "", nil, plan.srcType, plan.destType)
+	if err != nil {
+		return nil, false, fmt.Errorf("helper %s: %w", plan.name, err)
+	}
+	return append(body, codeNode{Kind: nodeKindReturn, Expr: "dst", WithError: true}), true, nil
+}
@@ -4,12 +4,20 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"go/ast"
 	"go/format"
+	"go/parser"
+	"go/token"
 	"go/types"
+	"maps"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
+
+	"golang.org/x/tools/go/ast/astutil"
 )
 
 // run orchestrates discovery, modeling, analysis, and file emission.
@@ -25,15 +33,18 @@ func (g *generator) run(cfg Config) error {
 	if err != nil {
 		return err
 	}
-	pkgs, err := loadDir(absDir)
+	pkgs, err := loadDir(absDir, cfg.ConvertPkgs...)
 	if err != nil {
 		return err
 	}
-	if len(pkgs) == 0 {
+	pkg, convertPkgs := splitLoaded(pkgs, absDir)
+	if pkg == nil {
 		return fmt.Errorf("no packages found in %s", absDir)
 	}
-	pkg := pkgs[0]
 	g.currentPkgName = pkg.Name
+	g.pkg = pkg.Types
+	g.fset = pkg.Fset
+	g.directives = collectDirectives(pkg.Syntax)
 
 	ifaceMap := map[string]*types.Interface{}
 	missing := []string{}
@@ -57,15 +68,22 @@ func (g *generator) run(cfg Config) error {
 	sort.Strings(cfg.Interfaces)
 	g.helperNames = make(map[string]string)
 	g.helperModels = nil
-	funcMap := g.discoverCustomFuncs(pkg, cfg.CustomFuncs)
-	for k, mi := range funcMap {
-		g.registry[k] = mi
-		if !mi.HasError {
-			base := strings.TrimSuffix(k, "#err")
-			if _, ok := g.registry[base]; !ok {
-				g.registry[base] = registryEntry{Name: mi.Name, HasError: false, Kind: regKindCustomFunc}
-			}
-		}
+	for _, cp := range convertPkgs {
+		maps.Copy(g.directives, collectDirectives(cp.Syntax))
+	}
+	if g.compose = cfg.Compose; g.compose == 1 || g.compose < 0 {
+		return fmt.Errorf("-compose %d: want 0 (disabled) or at least 2", g.compose)
+	}
+	switch g.nilPolicy = cfg.NilPolicy; g.nilPolicy {
+	case "", nilKeep, nilEmpty, nilNil:
+	default:
+		return fmt.Errorf("-nil %q: want keep, empty or nil", g.nilPolicy)
+	}
+	g.registerCustomFuncs(pkg.Types.Scope(), "", cfg.CustomFuncs)
+	// converter packages only fill conversions the mapped package lacks.
+	for _, cp := range convertPkgs {
+		g.convertPkgs = append(g.convertPkgs, cp.Types)
+		g.registerCustomFuncs(cp.Types.Scope(), g.addImport(cp.PkgPath, cp.Name), nil)
 	}
 
 	var interfaceModels []interfaceModel
@@ -81,7 +99,9 @@ func (g *generator) run(cfg Config) error {
 	}
 
 	// Populate helpers discovered during method planning (single-param struct, composite)
-	g.populateHelpers(pkg.Types.Scope())
+	if err := g.populateHelpers(pkg.Types.Scope()); err != nil {
+		return err
+	}
 	// Build method bodies from plans (may add helper plans during assignment building)
 	for i := range interfaceModels {
 		if err := g.populateMethods(&interfaceModels[i], allPlans[i]); err != nil {
@@ -89,7 +109,9 @@ func (g *generator) run(cfg Config) error {
 		}
 	}
 	// Populate any newly created helper plans
-	g.populateHelpers(pkg.Types.Scope())
+	if err := g.populateHelpers(pkg.Types.Scope()); err != nil {
+		return err
+	}
 
 	// De-duplicate helpers by name (defensive against accidental double population)
 	{
@@ -105,22 +127,26 @@ func (g *generator) run(cfg Config) error {
 	}
 	// Analyze helper error propagation (consolidated)
 	g.analyzeHelperErrors(&interfaceModels)
-
-	needCtx := false
-	for _, im := range interfaceModels {
-		for _, mm := range im.Methods {
-			if mm.HasContext {
-				needCtx = true
-				break
-			}
-		}
+	if err := g.checkMethodErrors(interfaceModels); err != nil {
+		return err
+	}
+	for i := range g.helperModels {
+		assignTempNames(g.helperModels[i].Body)
 	}
-	if needCtx {
-		for i := range g.helperModels {
-			g.helperModels[i].HasContext = true
+	for i := range interfaceModels {
+		for j := range interfaceModels[i].Methods {
+			assignTempNames(interfaceModels[i].Methods[j].Body)
 		}
 	}
 
+	usesCtx, err := g.analyzeHelperEnv(interfaceModels)
+	if err != nil {
+		return err
+	}
+	if usesCtx {
+		g.addImport("context", "context")
+	}
+
 	if cfg.Debug {
 		var assignPaths func(prefix string, nodes []codeNode)
 		assignPaths = func(prefix string, nodes []codeNode) {
@@ -144,14 +170,14 @@ func (g *generator) run(cfg Config) error {
 	}
 
 	data := fileModel{
-		Package:     pkg.Name,
-		Source:      strings.Join(cfg.Interfaces, ", "),
-		Helpers:     g.helperModels,
-		Interfaces:  interfaceModels,
-		NeedContext: needCtx,
-		Debug:       cfg.Debug,
-		Command:     cfg.Command,
-		Version:     cfg.Version,
+		Package:    pkg.Name,
+		Source:     strings.Join(cfg.Interfaces, ", "),
+		Imports:    g.importSpecs(),
+		Helpers:    g.helperModels,
+		Interfaces: interfaceModels,
+		Debug:      cfg.Debug,
+		Command:    cfg.Command,
+		Version:    cfg.Version,
 	}
 
 	var out bytes.Buffer
@@ -159,7 +185,7 @@ func (g *generator) run(cfg Config) error {
 		return err
 	}
 
-	formatted, err := format.Source(out.Bytes())
+	formatted, err := pruneImports(out.Bytes())
 	if err != nil {
 		formatted = out.Bytes()
 	}
@@ -170,3 +196,58 @@ func (g *generator) run(cfg Config) error {
 
 	return nil
 }
+
+// importSpecs returns the recorded imports sorted by path.
+func (g *generator) importSpecs() []importSpec {
+	specs := make([]importSpec, 0, len(g.imports))
+	for p, name := range g.imports {
+		spec := importSpec{Path: p}
+		if name != path.Base(p) {
+			spec.Name = name
+		}
+		specs = append(specs, spec)
+	}
+	sort.Slice(specs, func(i, j int) bool { return specs[i].Path < specs[j].Path })
+	return specs
+}
+
+// pruneImports drops imports the rendered file never references (packages
+// are recorded liberally while building type keys) and formats the result.
+func pruneImports(src []byte) ([]byte, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	used := map[string]bool{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok {
+				used[id.Name] = true
+			}
+		}
+		return true
+	})
+	for _, imp := range f.Imports {
+		p, _ := strconv.Unquote(imp.Path.Value)
+		name := path.Base(p)
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		if !used[name] {
+			astutil.DeleteNamedImport(fset, f, importName(imp), p)
+		}
+	}
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, f); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func importName(imp *ast.ImportSpec) string {
+	if imp.Name == nil {
+		return ""
+	}
+	return imp.Name.Name
+}
@@ -1,7 +1,12 @@
 package generator
 
 import (
+	"bytes"
 	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
 	"go/types"
 	"strings"
 	"unicode/utf8"
@@ -14,319 +19,878 @@ type AssignmentPlan struct {
 }
 
 // fieldResolver encapsulates reusable logic for resolving struct field mappings
-// using tags (map, mapsrc, mapfn) and source parameter discovery.
+// using tags (map, mapsrc, mapfn, mapdefault, mapexpr, mapif, mapagg) and source
+// parameter discovery.
 type fieldResolver struct{ g *generator }
 
 // helperStructPlans builds assignment plans for a helper mapping (single src
 // struct to dest struct).
-func (r *fieldResolver) helperStructPlans(plan helperPlan, scope *types.Scope) []AssignmentPlan {
+func (r *fieldResolver) helperStructPlans(plan helperPlan, scope *types.Scope) ([]AssignmentPlan, error) {
 	sStruct, _ := underlyingStruct(plan.srcType)
 	dStruct, _ := underlyingStruct(plan.destType)
 
 	if sStruct == nil || dStruct == nil {
-		return nil
+		return nil, nil
 	}
 
-	var plans []AssignmentPlan
+	resolve := func(path string) (sourcePath, bool) {
+		return walkSourcePath("in", plan.srcType, strings.Split(path, "."))
+	}
 
+	var plans []AssignmentPlan
 	for fi := 0; fi < dStruct.NumFields(); fi++ {
 		df := dStruct.Field(fi)
 		if !df.Exported() {
 			continue
 		}
-
 		fname := df.Name()
+		tags := parseTagCached(dStruct, fi)
 
-		explicitFunc := ""
-		explicitSrcPath := ""
-		if pt := parseTagCached(dStruct, fi); pt != nil {
-			if fn := pt["mapfn"]; fn != "" {
-				explicitFunc = fn
-			}
-			if p := pt["mapsrc"]; p != "" {
-				explicitSrcPath = p
-			}
+		nodes, src, err := r.aggOrFieldNodes("dst."+fname, df, tags, resolve, func() ([]codeNode, *sourcePath, error) {
+			return r.helperFieldNodes(plan, sStruct, df, tags)
+		})
+		if err == nil && tags["mapif"] != "" {
+			nodes, err = r.conditionNodes(tags["mapif"], nodes, src, resolve, scope)
 		}
-
-		sf := findMatchingSourceField(sStruct, fname)
-		if sf == nil {
-			sf = findTaggedSourceField(sStruct, fname)
-		}
-		if sf == nil {
-			if pt := parseTagCached(dStruct, fi); pt != nil {
-				if sourceName := pt["map"]; sourceName != "" {
-					sf = findMatchingSourceField(sStruct, sourceName)
-					if sf == nil {
-						rRunes := []rune(sourceName)
-						if len(rRunes) > 0 {
-							first, _ := utf8.DecodeRuneInString(string(rRunes[0]))
-							upper := strings.ToUpper(string(first))
-							rRunes[0], _ = utf8.DecodeRuneInString(upper)
-							sf = findMatchingSourceField(sStruct, string(rRunes))
-						}
-					}
-				}
-			}
+		if err != nil {
+			return nil, fmt.Errorf("helper %s: field %s: %w", plan.name, fname, err)
 		}
+		plans = append(plans, AssignmentPlan{DestField: fname, Nodes: nodes})
+	}
 
-		if explicitSrcPath != "" && explicitFunc == "" {
-			parts := strings.Split(explicitSrcPath, ".")
-			currType := plan.srcType
-			expr := "in"
-			okPath := true
-			for _, seg := range parts {
-				s, _ := underlyingStruct(currType)
-				if s == nil {
-					okPath = false
-					break
-				}
-				var f *types.Var
-				for fj := 0; fj < s.NumFields(); fj++ {
-					ff := s.Field(fj)
-					if ff.Exported() && ff.Name() == seg {
-						f = ff
-						break
-					}
-				}
-				if f == nil {
-					okPath = false
-					break
-				}
-				expr += "." + seg
-				currType = f.Type()
-			}
-			if okPath {
-				nodes := r.g.buildAssignmentNodes("dst."+fname, expr, df.Type(), currType, "", false)
-				plans = append(plans, AssignmentPlan{DestField: fname, Nodes: nodes})
-				continue
-			}
-		}
+	return plans, nil
+}
 
-		if sf == nil && explicitFunc == "" {
-			plans = append(plans, AssignmentPlan{DestField: fname, Nodes: []codeNode{{Kind: nodeKindComment, Comment: "no source field for " + fname}}})
-			continue
-		}
+// helperFieldNodes resolves a single destination field of a helper mapping and
+// returns its nodes along with the resolved single source (nil when the field
+// has no source or several candidates).
+func (r *fieldResolver) helperFieldNodes(plan helperPlan, sStruct *types.Struct, df *types.Var, tags map[string]string) ([]codeNode, *sourcePath, error) {
+	fname := df.Name()
+	explicitSrcPath := tags["mapsrc"]
+	def := tags["mapdefault"]
+	if expr := tags["mapexpr"]; expr != "" {
+		nodes, err := r.exprNodes("dst."+fname, df, expr, []exprParam{{name: "in", typ: plan.srcType}}, r.g.scopePos(df))
+		return nodes, nil, err
+	}
 
-		if explicitFunc != "" {
-			resolved := false
-			if scope != nil {
-				if obj := scope.Lookup(explicitFunc); obj != nil {
-					if fn, ok := obj.(*types.Func); ok {
-						if sig, ok := fn.Type().(*types.Signature); ok && sig.Params().Len() == 1 && sig.Results().Len() >= 1 {
-							if sig.Results().Len() == 1 || (sig.Results().Len() == 2 && isErrorType(sig.Results().At(1).Type())) {
-								withErr := sig.Results().Len() == 2
-								switch dd := df.Type().(type) {
-								case *types.Slice:
-									child := []codeNode{{Kind: nodeKindAssignFunc, Dest: "mapped", Method: explicitFunc, Arg: "v", WithError: withErr}}
-									plans = append(plans, AssignmentPlan{DestField: fname, Nodes: []codeNode{{Kind: nodeKindSliceMap, Src: "in." + sf.Name(), Dest: "dst." + fname, DestType: types.TypeString(dd, r.g.qualifier), ElemType: types.TypeString(dd.Elem(), r.g.qualifier), Children: child, LoopWithError: withErr}}})
-									resolved = true
-								case *types.Map:
-									child := []codeNode{{Kind: nodeKindAssignFunc, Dest: "mapped", Method: explicitFunc, Arg: "v", WithError: withErr}}
-									plans = append(plans, AssignmentPlan{DestField: fname, Nodes: []codeNode{{Kind: nodeKindMapMap, Src: "in." + sf.Name(), Dest: "dst." + fname, DestType: types.TypeString(dd, r.g.qualifier), ElemType: types.TypeString(dd.Elem(), r.g.qualifier), Children: child, LoopWithError: withErr}}})
-									resolved = true
-								default:
-									srcExpr := "in." + sf.Name()
-									nodes := []codeNode{{Kind: nodeKindAssignFunc, Dest: "dst." + fname, Method: explicitFunc, Arg: srcExpr, WithError: withErr}}
-									plans = append(plans, AssignmentPlan{DestField: fname, Nodes: nodes})
-									resolved = true
-								}
-							}
-						}
-					}
+	sf := findMatchingSourceField(sStruct, fname)
+	if sf == nil {
+		sf = findTaggedSourceField(sStruct, fname)
+	}
+	if sf == nil {
+		if sourceName := tags["map"]; sourceName != "" {
+			sf = findMatchingSourceField(sStruct, sourceName)
+			if sf == nil {
+				rRunes := []rune(sourceName)
+				if len(rRunes) > 0 {
+					first, _ := utf8.DecodeRuneInString(string(rRunes[0]))
+					upper := strings.ToUpper(string(first))
+					rRunes[0], _ = utf8.DecodeRuneInString(upper)
+					sf = findMatchingSourceField(sStruct, string(rRunes))
 				}
 			}
+		}
+	}
 
-			if !resolved {
-				plans = append(plans, AssignmentPlan{DestField: fname, Nodes: []codeNode{{Kind: nodeKindComment, Comment: "mapfn not found or invalid: " + explicitFunc}}})
+	if explicitSrcPath != "" {
+		if strings.Contains(explicitSrcPath, "|") {
+			var cands []sourcePath
+			for _, c := range strings.Split(explicitSrcPath, "|") {
+				sp, ok := walkSourcePath("in", plan.srcType, strings.Split(strings.TrimSpace(c), "."))
+				if !ok {
+					return nil, nil, fmt.Errorf("mapsrc candidate %q not found", c)
+				}
+				cands = append(cands, sp)
 			}
-			continue
+			nodes, err := r.fieldNodes("dst."+fname, df, cands, tags, assignOpts{nilPolicy: plan.nilPolicy})
+			return nodes, nil, err
+		}
+		if sp, ok := walkSourcePath("in", plan.srcType, strings.Split(explicitSrcPath, ".")); ok {
+			nodes, err := r.fieldNodes("dst."+fname, df, []sourcePath{sp}, tags, assignOpts{nilPolicy: plan.nilPolicy})
+			return nodes, &sp, err
 		}
+	}
 
-		if sf != nil {
-			nodes := r.g.buildAssignmentNodes("dst."+fname, "in."+sf.Name(), df.Type(), sf.Type(), "", false)
-			plans = append(plans, AssignmentPlan{DestField: fname, Nodes: nodes})
+	if sf == nil {
+		if def != "" {
+			nodes, err := r.fieldNodes("dst."+fname, df, nil, tags, assignOpts{nilPolicy: plan.nilPolicy})
+			return nodes, nil, err
 		}
+		return []codeNode{{Kind: nodeKindComment, Comment: "no source field for " + fname}}, nil, nil
 	}
 
-	return plans
+	src := sourcePath{expr: "in." + sf.Name(), typ: sf.Type()}
+	nodes, err := r.fieldNodes("dst."+fname, df, []sourcePath{src}, tags, assignOpts{nilPolicy: plan.nilPolicy})
+	return nodes, &src, err
 }
 
-// methodStructPlans resolves field assignments for a multi-param struct mapping
-// method. It encapsulates the prior inline logic for mapsrc handling and
-// fallback heuristics.
-func (r *fieldResolver) methodStructPlans(mp *methodPlan, sig *types.Signature, destStruct *types.Struct, destPtr bool, params []paramModel, ctxIndex int, primaryName string, useCtx bool) ([]AssignmentPlan, error) {
+// methodStructPlans resolves field assignments for an inline struct mapping
+// method (multiple params or per-method overrides), covering mapsrc handling
+// and fallback heuristics.
+func (r *fieldResolver) methodStructPlans(mp *methodPlan, sig *types.Signature, destStruct *types.Struct, params []paramModel, ctxIndex int, primaryName string) ([]AssignmentPlan, error) {
 	var plans []AssignmentPlan
 
 	// build param struct lookup
 	paramStructs := map[string]*types.Struct{}
-	paramPtrs := map[string]bool{}
 	for i := 0; i < sig.Params().Len(); i++ {
 		if i == ctxIndex {
 			continue
 		}
-		pname := params[i].Name
-		if s, isPtr := underlyingStruct(sig.Params().At(i).Type()); s != nil {
-			paramStructs[pname] = s
-			paramPtrs[pname] = isPtr
+		if s, _ := underlyingStruct(sig.Params().At(i).Type()); s != nil {
+			paramStructs[params[i].Name] = s
 		}
 	}
 
+	// resolve accepts parameters, "param.Field" paths or fields of the
+	// primary param.
+	resolve := func(path string) (sourcePath, bool) {
+		if sp, ok := methodSourcePath(path, sig, params, ctxIndex); ok {
+			return sp, true
+		}
+		return walkSourcePath(primaryName, sig.Params().At(mp.primaryIndex).Type(), strings.Split(path, "."))
+	}
+
 	for i := 0; i < destStruct.NumFields(); i++ {
 		df := destStruct.Field(i)
 		if !df.Exported() {
 			continue
 		}
 		fname := df.Name()
-		tag := destStruct.Tag(i)
-		parsed := parseTag(tag)
-		mapsrc := parsed["mapsrc"]
-		var srcParamName, srcFieldName string
-
-		if mapsrc != "" {
-			parts := strings.Split(mapsrc, ".")
-			paramToken := parts[0]
-			if strings.HasPrefix(paramToken, "p") {
-				idxStr := strings.TrimPrefix(paramToken, "p")
-				if idxStr != "" {
-					nonCtx := []paramModel{}
-					for pi, p := range params {
-						if pi == ctxIndex {
-							continue
-						}
-						nonCtx = append(nonCtx, p)
-					}
-					var idx int
-					if _, err := fmt.Sscanf(idxStr, "%d", &idx); err == nil {
-						if idx >= 0 && idx < len(nonCtx) {
-							srcParamName = nonCtx[idx].Name
-						}
-					}
-				}
-			}
-			if srcParamName == "" {
-				for _, p := range params {
-					if p.Name == paramToken {
-						srcParamName = p.Name
-						break
-					}
-				}
+		tags := parseTagCached(destStruct, i)
+
+		nodes, src, err := r.aggOrFieldNodes("dst."+fname, df, tags, resolve, func() ([]codeNode, *sourcePath, error) {
+			return r.methodFieldNodes(mp, sig, df, tags, params, paramStructs, ctxIndex, primaryName)
+		})
+		if err == nil && tags["mapfn"] != "" && !mp.hasError && hasErrorNode(nodes) {
+			err = fmt.Errorf("mapfn %s returns an error but the method does not", tags["mapfn"])
+		}
+		if err == nil && tags["mapif"] != "" {
+			nodes, err = r.conditionNodes(tags["mapif"], nodes, src, resolve, r.g.pkg.Scope())
+		}
+		if err != nil {
+			return nil, fmt.Errorf("method %s: field %s: %w", mp.name, fname, err)
+		}
+		plans = append(plans, AssignmentPlan{DestField: fname, Nodes: nodes})
+	}
+
+	return plans, nil
+}
+
+// methodFieldNodes resolves a single destination field of an inline method
+// body, returning its nodes and the resolved single source (if any).
+func (r *fieldResolver) methodFieldNodes(mp *methodPlan, sig *types.Signature, df *types.Var, tags map[string]string, params []paramModel, paramStructs map[string]*types.Struct, ctxIndex int, primaryName string) ([]codeNode, *sourcePath, error) {
+	fname := df.Name()
+	destExpr := "dst." + fname
+	mapsrc := tags["mapsrc"]
+	def := tags["mapdefault"]
+	var srcParamName, srcFieldName string
+
+	// emit maps the resolved candidates (in priority order) onto the field,
+	// falling back to the declared default when all are zero.
+	emit := func(cands ...sourcePath) ([]codeNode, *sourcePath, error) {
+		nodes, err := r.fieldNodes(destExpr, df, cands, tags, assignOpts{method: mp.name, qual: mp.qualifier, nilPolicy: mp.nilPolicy, keyTag: mp.keyTag})
+		if len(cands) == 1 {
+			return nodes, &cands[0], err
+		}
+		return nodes, nil, err
+	}
+	// noSource records an unresolved field unless a default covers it.
+	noSource := func(comment string) ([]codeNode, *sourcePath, error) {
+		if def != "" {
+			return emit()
+		}
+		return []codeNode{{Kind: nodeKindComment, Comment: comment}}, nil, nil
+	}
+
+	expr, exprPos := mp.fieldExprs[fname], mp.pos
+	if expr == "" {
+		expr, exprPos = tags["mapexpr"], r.g.scopePos(df)
+	}
+	if expr != "" {
+		scope := make([]exprParam, 0, len(params)+1)
+		hasIn := false
+		for pi, p := range params {
+			scope = append(scope, exprParam{name: p.Name, typ: sig.Params().At(pi).Type()})
+			hasIn = hasIn || p.Name == "in"
+		}
+		if !hasIn {
+			// "in" refers to the primary source, as in helper bodies.
+			scope = append(scope, exprParam{name: "in", typ: sig.Params().At(mp.primaryIndex).Type(), emit: primaryName})
+		}
+		nodes, err := r.exprNodes(destExpr, df, expr, scope, exprPos)
+		return nodes, nil, err
+	}
+
+	if strings.Contains(mapsrc, "|") {
+		var cands []sourcePath
+		for _, c := range strings.Split(mapsrc, "|") {
+			sp, ok := methodSourcePath(strings.TrimSpace(c), sig, params, ctxIndex)
+			if !ok {
+				return nil, nil, fmt.Errorf("mapsrc candidate %q not found", c)
 			}
-			pathParts := []string{}
-			if len(parts) > 1 {
-				pathParts = parts[1:]
+			cands = append(cands, sp)
+		}
+		return emit(cands...)
+	}
+
+	if mapsrc != "" {
+		parts := strings.Split(mapsrc, ".")
+		if pi := methodParamByToken(parts[0], params, ctxIndex); pi >= 0 {
+			srcParamName = params[pi].Name
+		}
+		if len(parts) > 1 {
+			if sp, ok := methodSourcePath(mapsrc, sig, params, ctxIndex); ok {
+				return emit(sp)
 			}
-			if len(pathParts) > 0 {
-				paramIdx := -1
-				for pi := 0; pi < sig.Params().Len(); pi++ {
-					if params[pi].Name == srcParamName {
-						paramIdx = pi
-						break
-					}
-				}
-				if paramIdx >= 0 {
-					currType := sig.Params().At(paramIdx).Type()
-					expr := srcParamName
-					okPath := true
-					for _, seg := range pathParts {
-						s, _ := underlyingStruct(currType)
-						if s == nil {
-							okPath = false
-							break
-						}
-						var f *types.Var
-						for fj := 0; fj < s.NumFields(); fj++ {
-							ff := s.Field(fj)
-							if ff.Exported() && ff.Name() == seg {
-								f = ff
-								break
-							}
-						}
-						if f == nil {
-							okPath = false
-							break
-						}
-						expr += "." + seg
-						currType = f.Type()
-					}
-					if okPath {
-						nodes := r.g.buildAssignmentNodes(prefixDest(destPtr)+fname, expr, df.Type(), currType, mp.name, useCtx)
-						plans = append(plans, AssignmentPlan{DestField: fname, Nodes: nodes})
-						continue
-					}
-				}
+		}
+		if len(parts) == 2 {
+			srcFieldName = parts[1]
+		}
+	}
+
+	if srcParamName == "" {
+		srcParamName = primaryName
+	}
+	if srcFieldName == "" {
+		srcFieldName = fname
+	}
+
+	sStruct := paramStructs[srcParamName]
+	if sStruct == nil {
+		return noSource("no struct param for " + fname)
+	}
+
+	if sf := findMatchingSourceField(sStruct, srcFieldName); sf != nil {
+		return emit(sourcePath{expr: srcParamName + "." + sf.Name(), typ: sf.Type()})
+	}
+	// attempt other params.
+	for _, p := range params {
+		if p.Name == srcParamName {
+			continue
+		}
+		if ss := paramStructs[p.Name]; ss != nil {
+			if f2 := findMatchingSourceField(ss, fname); f2 != nil {
+				return emit(sourcePath{expr: p.Name + "." + f2.Name(), typ: f2.Type()})
 			}
-			if len(pathParts) == 1 {
-				srcFieldName = pathParts[0]
+		}
+	}
+	for idx, p := range params {
+		if idx == ctxIndex {
+			continue
+		}
+		if pt := sig.Params().At(idx).Type(); types.Identical(pt, df.Type()) {
+			return emit(sourcePath{expr: p.Name, typ: pt})
+		}
+	}
+	return noSource("no source for " + fname)
+}
+
+// conditionNodes wraps a field assignment in its mapif condition: "nonzero"
+// (skip zero sources), a bool source path (negated with a leading "!"), or a
+// predicate function func(S) bool applied to the source value. When the
+// condition fails the destination keeps its prior (zero) value.
+func (r *fieldResolver) conditionNodes(raw string, nodes []codeNode, src *sourcePath, resolve func(string) (sourcePath, bool), scope *types.Scope) ([]codeNode, error) {
+	if raw == "nonzero" {
+		if src == nil {
+			return nil, fmt.Errorf("mapif nonzero requires a single source field")
+		}
+		check, err := r.g.nonZeroCheck(src.expr, src.typ)
+		if err != nil {
+			return nil, err
+		}
+		return guardNodes(append(append([]string(nil), src.guards...), check), nodes), nil
+	}
+
+	name, negate := strings.CutPrefix(raw, "!")
+	not := ""
+	if negate {
+		not = "!"
+	}
+	if sp, ok := resolve(name); ok {
+		if b, ok := sp.typ.Underlying().(*types.Basic); !ok || b.Info()&types.IsBoolean == 0 {
+			return nil, fmt.Errorf("mapif %q: source %s is not a bool", raw, sp.expr)
+		}
+		return guardNodes(append(append([]string(nil), sp.guards...), not+sp.expr), nodes), nil
+	}
+
+	var fn *types.Func
+	if scope != nil {
+		fn, _ = scope.Lookup(name).(*types.Func)
+	}
+	if fn == nil {
+		return nil, fmt.Errorf("mapif %q: no bool source field or predicate function", raw)
+	}
+	sig := fn.Type().(*types.Signature)
+	if sig.Params().Len() != 1 || sig.Results().Len() != 1 || !types.Identical(sig.Results().At(0).Type().Underlying(), types.Typ[types.Bool]) {
+		return nil, fmt.Errorf("mapif %q: predicate must have signature func(T) bool", raw)
+	}
+	if src == nil || !types.AssignableTo(src.typ, sig.Params().At(0).Type()) {
+		return nil, fmt.Errorf("mapif %q: predicate does not accept the source value", raw)
+	}
+	return guardNodes(append(append([]string(nil), src.guards...), not+name+"("+src.expr+")"), nodes), nil
+}
+
+// aggOrFieldNodes resolves a field from its mapagg aggregate when tagged and
+// with field otherwise.
+func (r *fieldResolver) aggOrFieldNodes(destExpr string, df *types.Var, tags map[string]string, resolve func(string) (sourcePath, bool), field func() ([]codeNode, *sourcePath, error)) ([]codeNode, *sourcePath, error) {
+	if agg := tags["mapagg"]; agg != "" {
+		nodes, err := r.aggNodes(destExpr, df, agg, resolve)
+		return nodes, nil, err
+	}
+	return field()
+}
+
+// aggNodes fills a field with an aggregate of a source collection (mapagg):
+// "len(Path)", or "Fn(Path)" folding the elements (map values, in no defined
+// order) with a reducer func(acc D, e E) D[, error] starting from the zero D.
+// The length is converted to the field type; D must be assignable to it.
+func (r *fieldResolver) aggNodes(destExpr string, df *types.Var, raw string, resolve func(string) (sourcePath, bool)) ([]codeNode, error) {
+	name, rest, ok := strings.Cut(raw, "(")
+	path, closed := strings.CutSuffix(rest, ")")
+	if !ok || !closed {
+		return nil, fmt.Errorf("mapagg %q: want len(Path) or Reducer(Path)", raw)
+	}
+	name = strings.TrimSpace(name)
+	sp, ok := resolve(strings.TrimSpace(path))
+	if !ok {
+		return nil, fmt.Errorf("mapagg %q: source %s not found", raw, path)
+	}
+	var elem types.Type
+	switch st := sp.typ.Underlying().(type) {
+	case *types.Slice:
+		elem = st.Elem()
+	case *types.Array:
+		elem = st.Elem()
+	case *types.Map:
+		elem = st.Elem()
+	}
+	if elem == nil {
+		return nil, fmt.Errorf("mapagg %q: %s is not a collection", raw, sp.expr)
+	}
+
+	if name == "len" {
+		// lengths convert to any integer type.
+		if b, ok := df.Type().Underlying().(*types.Basic); !ok || b.Info()&types.IsInteger == 0 {
+			return nil, fmt.Errorf("mapagg %q: cannot assign int to %s", raw, types.TypeString(df.Type(), r.g.qualifier))
+		}
+		n := codeNode{Kind: nodeKindAssignCast, Dest: destExpr, Src: "len(" + sp.expr + ")", CastType: types.TypeString(df.Type(), r.g.qualifier)}
+		if types.Identical(df.Type(), types.Typ[types.Int]) {
+			n.Kind = nodeKindAssignDirect
+		}
+		return guardNodes(sp.guards, []codeNode{n}), nil
+	}
+
+	fn, call, err := r.g.lookupFunc(name, df.Pos())
+	if err != nil {
+		return nil, fmt.Errorf("mapagg %s: %w", name, err)
+	}
+	sig := fn.Type().(*types.Signature)
+	params, results := sig.Params(), sig.Results()
+	switch {
+	case r.g.methodEnv(fn) != "":
+		return nil, fmt.Errorf("mapagg %s: reducer must be a function", name)
+	case params.Len() != 2 || results.Len() < 1 || results.Len() > 2 || !types.Identical(params.At(0).Type(), results.At(0).Type()) || (results.Len() == 2 && !isErrorType(results.At(1).Type())):
+		return nil, fmt.Errorf("mapagg %s: want reducer func(acc D, e E) D[, error], have %s", name, types.TypeString(sig, r.g.qualifier))
+	case !types.AssignableTo(elem, params.At(1).Type()):
+		return nil, fmt.Errorf("mapagg %s: reducer does not accept %s", name, types.TypeString(elem, r.g.qualifier))
+	case !types.AssignableTo(results.At(0).Type(), df.Type()):
+		return nil, fmt.Errorf("mapagg %s: cannot assign %s to %s", name, types.TypeString(results.At(0).Type(), r.g.qualifier), types.TypeString(df.Type(), r.g.qualifier))
+	}
+	return r.g.inLoop(func(loop string) ([]codeNode, error) {
+		return guardNodes(sp.guards, []codeNode{{Kind: nodeKindReduce, Src: sp.expr, Dest: destExpr, Method: call, ElemType: types.TypeString(results.At(0).Type(), r.g.qualifier), WithError: results.Len() == 2, Loop: loop}}), nil
+	})
+}
+
+// sourcePath is a resolved source expression together with the nil guards
+// that must hold before it can be evaluated.
+type sourcePath struct {
+	expr   string
+	typ    types.Type
+	guards []string
+}
+
+// walkSourcePath follows exported field segments from root, recording a nil
+// guard for every intermediate pointer hop (root itself is guarded by the
+// caller).
+func walkSourcePath(root string, rootType types.Type, segs []string) (sourcePath, bool) {
+	sp := sourcePath{expr: root, typ: rootType}
+	for _, seg := range segs {
+		s, isPtr := underlyingStruct(sp.typ)
+		if s == nil {
+			return sourcePath{}, false
+		}
+		f := findMatchingSourceField(s, seg)
+		if f == nil {
+			return sourcePath{}, false
+		}
+		if isPtr && sp.expr != root {
+			sp.guards = append(sp.guards, sp.expr+" != nil")
+		}
+		sp.expr += "." + seg
+		sp.typ = f.Type()
+	}
+	return sp, true
+}
+
+// methodParamByToken resolves a mapsrc parameter token (a parameter name or a
+// zero-based pN index over non-context params) to a parameter position, or -1.
+func methodParamByToken(token string, params []paramModel, ctxIndex int) int {
+	if idxStr, ok := strings.CutPrefix(token, "p"); ok && idxStr != "" {
+		var nonCtx []int
+		for pi := range params {
+			if pi != ctxIndex {
+				nonCtx = append(nonCtx, pi)
 			}
 		}
+		var idx int
+		if _, err := fmt.Sscanf(idxStr, "%d", &idx); err == nil && idx >= 0 && idx < len(nonCtx) {
+			return nonCtx[idx]
+		}
+	}
+	for pi, p := range params {
+		if p.Name == token {
+			return pi
+		}
+	}
+	return -1
+}
 
-		if srcParamName == "" {
-			srcParamName = primaryName
+// methodSourcePath resolves a "param.Field.Field" mapsrc path against the
+// method parameters.
+func methodSourcePath(path string, sig *types.Signature, params []paramModel, ctxIndex int) (sourcePath, bool) {
+	parts := strings.Split(path, ".")
+	pi := methodParamByToken(parts[0], params, ctxIndex)
+	if pi < 0 || pi == ctxIndex {
+		return sourcePath{}, false
+	}
+	return walkSourcePath(params[pi].Name, sig.Params().At(pi).Type(), parts[1:])
+}
+
+// guardNodes wraps nodes in a conditional when the source path has nil guards.
+func guardNodes(guards []string, nodes []codeNode) []codeNode {
+	if len(guards) == 0 {
+		return nodes
+	}
+	return []codeNode{{Kind: nodeKindCond, Children: []codeNode{{Kind: nodeKindBranch, Expr: strings.Join(guards, " && "), Children: nodes}}}}
+}
+
+// fieldNodes maps source candidates onto a destination field, honoring the
+// optional mapfn chain or qualifier ("@name") and mapdefault value. A single
+// candidate without default keeps the plain assignment shape, and a mapfilter
+// skips the elements of a collection mapped element by element.
+func (r *fieldResolver) fieldNodes(destExpr string, df *types.Var, cands []sourcePath, tags map[string]string, opts assignOpts) ([]codeNode, error) {
+	switch opts.dup = tags["mapdup"]; opts.dup {
+	case "", mapDupError, mapDupFirst, mapDupLast:
+	default:
+		return nil, fmt.Errorf("mapdup %q: want error, first or last", opts.dup)
+	}
+	switch opts.length = tags["maplen"]; opts.length {
+	case "", mapLenError, mapLenPad, mapLenTruncate:
+	default:
+		return nil, fmt.Errorf("maplen %q: want error, pad or truncate", opts.length)
+	}
+	switch p := tags["mapnil"]; p {
+	case "":
+	case nilKeep, nilEmpty, nilNil:
+		opts.nilPolicy = p
+	default:
+		return nil, fmt.Errorf("mapnil %q: want keep, empty or nil", p)
+	}
+	opts.key, opts.order = tags["mapkey"], tags["maporder"]
+	opts.variants, opts.unknown, opts.pos = tags["mapvariants"], tags["mapunknown"], r.g.scopePos(df)
+	if opts.path = df.Name(); len(cands) == 1 {
+		_, opts.path, _ = strings.Cut(cands[0].expr, ".")
+	}
+	if raw := tags["mapfilter"]; raw != "" {
+		if len(cands) != 1 {
+			return nil, fmt.Errorf("mapfilter requires a single source")
 		}
-		if srcFieldName == "" {
-			srcFieldName = fname
+		filter, err := r.filterFunc(raw, cands[0].typ, df.Pos())
+		if err != nil {
+			return nil, err
 		}
+		opts.filter = filter
+	}
+	nodes, err := r.fieldConvNodes(destExpr, df, cands, tags, opts)
+	if err == nil && opts.filter != nil && !filtered(nodes) {
+		err = fmt.Errorf("mapfilter %s: field is not mapped element by element", tags["mapfilter"])
+	}
+	return nodes, err
+}
 
-		sStruct := paramStructs[srcParamName]
-		if sStruct == nil {
-			plans = append(plans, AssignmentPlan{DestField: fname, Nodes: []codeNode{{Kind: nodeKindComment, Comment: "no struct param for " + fname}}})
-			continue
+// fieldConvNodes converts the candidates of fieldNodes with the field's
+// settings applied to opts.
+func (r *fieldResolver) fieldConvNodes(destExpr string, df *types.Var, cands []sourcePath, tags map[string]string, opts assignOpts) ([]codeNode, error) {
+	def := tags["mapdefault"]
+	if fn := tags["mapfn"]; fn != "" {
+		switch {
+		case def != "":
+			return nil, fmt.Errorf("mapdefault cannot be combined with mapfn")
+		case len(cands) != 1:
+			return nil, fmt.Errorf("mapfn requires a single source")
+		}
+		if named, ok := strings.CutPrefix(fn, "@"); ok {
+			opts.qual = named
+			nodes, err := r.g.buildAssignmentNodes(destExpr, cands[0].expr, df.Type(), cands[0].typ, opts)
+			if err != nil {
+				return nil, err
+			}
+			if !r.g.usesNamed(nodes, named) {
+				return nil, fmt.Errorf("mapfn %s: no converter named %s for %s -> %s", fn, named, types.TypeString(cands[0].typ, r.g.qualifier), types.TypeString(df.Type(), r.g.qualifier))
+			}
+			return guardNodes(cands[0].guards, nodes), nil
+		}
+		if opts.filter != nil {
+			return nil, fmt.Errorf("mapfilter cannot be combined with mapfn functions")
 		}
+		nodes, err := r.mapfnNodes(destExpr, df.Type(), cands[0], fn, df.Pos(), opts)
+		if err != nil {
+			return nil, err
+		}
+		return guardNodes(cands[0].guards, nodes), nil
+	}
+	if def == "" && len(cands) == 1 {
+		nodes, err := r.g.buildAssignmentNodes(destExpr, cands[0].expr, df.Type(), cands[0].typ, opts)
+		if err != nil {
+			return nil, err
+		}
+		if opts.key != "" && !hasNodeKind(nodes, nodeKindSliceToMap) {
+			return nil, fmt.Errorf("mapkey %s: field does not map a slice to a map", opts.key)
+		}
+		return guardNodes(cands[0].guards, nodes), nil
+	}
+	var defNodes []codeNode
+	if def != "" {
+		var err error
+		if defNodes, err = r.defaultNodes(destExpr, df, def); err != nil {
+			return nil, err
+		}
+		if len(cands) == 0 {
+			return defNodes, nil
+		}
+	}
+	return r.fallbackNodes(destExpr, df.Type(), cands, defNodes, opts)
+}
 
-		var sf *types.Var
-		for j := 0; j < sStruct.NumFields(); j++ {
-			f := sStruct.Field(j)
-			if f.Exported() && f.Name() == srcFieldName {
-				sf = f
-				break
+// filterFunc resolves a mapfilter ("nonnil", "nonzero" or a predicate
+// func(E) bool, negated with a leading "!"; several are combined with commas)
+// against the elements (or map values) of the source collection.
+func (r *fieldResolver) filterFunc(raw string, srcType types.Type, pos token.Pos) (func(string) string, error) {
+	var elem types.Type
+	switch st := srcType.Underlying().(type) {
+	case *types.Slice:
+		elem = st.Elem()
+	case *types.Map:
+		elem = st.Elem()
+	default:
+		return nil, fmt.Errorf("mapfilter %s: source is not a slice or map", raw)
+	}
+	var checks []func(string) string
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		switch part {
+		case "nonnil":
+			switch elem.Underlying().(type) {
+			case *types.Pointer, *types.Interface, *types.Slice, *types.Map, *types.Chan, *types.Signature:
+			default:
+				return nil, fmt.Errorf("mapfilter nonnil: %s cannot be nil", types.TypeString(elem, r.g.qualifier))
+			}
+			checks = append(checks, func(v string) string { return v + " != nil" })
+		case "nonzero":
+			if _, err := r.g.nonZeroCheck("v", elem); err != nil {
+				return nil, fmt.Errorf("mapfilter nonzero: %w", err)
+			}
+			checks = append(checks, func(v string) string {
+				check, _ := r.g.nonZeroCheck(v, elem)
+				return check
+			})
+		default:
+			name, negate := strings.CutPrefix(part, "!")
+			fn, call, err := r.g.lookupFunc(name, pos)
+			if err != nil {
+				return nil, fmt.Errorf("mapfilter %s: %w", name, err)
+			}
+			sig := fn.Type().(*types.Signature)
+			switch {
+			case r.g.methodEnv(fn) != "":
+				return nil, fmt.Errorf("mapfilter %s: predicate must be a function", name)
+			case sig.Params().Len() != 1 || sig.Results().Len() != 1 || !types.Identical(sig.Results().At(0).Type().Underlying(), types.Typ[types.Bool]):
+				return nil, fmt.Errorf("mapfilter %s: predicate must have signature func(T) bool", name)
+			case !types.AssignableTo(elem, sig.Params().At(0).Type()):
+				return nil, fmt.Errorf("mapfilter %s: predicate does not accept %s", name, types.TypeString(elem, r.g.qualifier))
+			}
+			not := ""
+			if negate {
+				not = "!"
 			}
+			checks = append(checks, func(v string) string { return not + call + "(" + v + ")" })
 		}
-		if sf == nil {
-			resolved := false
-			// attempt other params.
-			for _, p := range params {
-				if p.Name == srcParamName {
-					continue
-				}
-				ss := paramStructs[p.Name]
-				if ss == nil {
-					continue
-				}
-				for jj := 0; jj < ss.NumFields(); jj++ {
-					f2 := ss.Field(jj)
-					if f2.Exported() && f2.Name() == fname {
-						nodes := r.g.buildAssignmentNodes(prefixDest(destPtr)+fname, fmt.Sprintf("%s.%s", p.Name, f2.Name()), df.Type(), f2.Type(), mp.name, useCtx)
-						plans = append(plans, AssignmentPlan{DestField: fname, Nodes: nodes})
-						resolved = true
-						break
-					}
-				}
-				if resolved {
-					break
+	}
+	return func(v string) string {
+		conds := make([]string, len(checks))
+		for i, check := range checks {
+			conds[i] = check(v)
+		}
+		return strings.Join(conds, " && ")
+	}, nil
+}
+
+// filtered reports whether nodes map a collection under a mapfilter.
+func filtered(nodes []codeNode) bool {
+	for i := range nodes {
+		if (nodes[i].Kind == nodeKindSliceMap || nodes[i].Kind == nodeKindMapMap) && nodes[i].Expr != "" || filtered(nodes[i].Children) {
+			return true
+		}
+	}
+	return false
+}
+
+// mapfnStep is a single function of a mapfn chain.
+type mapfnStep struct {
+	call string
+	sig  *types.Signature
+	env  string // environment value owning a method step
+}
+
+// param returns the type of the converted value (after an optional context).
+func (s mapfnStep) param() types.Type {
+	return s.sig.Params().At(s.sig.Params().Len() - 1).Type()
+}
+
+// mapfnNodes converts a source with a mapfn chain ("Fn" or
+// "strings.TrimSpace,strings.ToLower,Fn"): each step's result feeds the next
+// and any step may return an error. When the first step does not accept the
+// source itself, the chain is applied to each slice element or map value.
+func (r *fieldResolver) mapfnNodes(destExpr string, destType types.Type, src sourcePath, raw string, pos token.Pos, opts assignOpts) ([]codeNode, error) {
+	var steps []mapfnStep
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		fn, call, err := r.g.lookupFunc(name, pos)
+		if err != nil {
+			return nil, fmt.Errorf("mapfn %s: %w", name, err)
+		}
+		sig := fn.Type().(*types.Signature)
+		if !isConverterSig(sig) {
+			return nil, fmt.Errorf("mapfn %s: want func([context.Context,] S) (D[, error]), have %s", name, types.TypeString(sig, r.g.qualifier))
+		}
+		steps = append(steps, mapfnStep{call: call, sig: sig, env: r.g.methodEnv(fn)})
+	}
+
+	if !types.AssignableTo(src.typ, steps[0].param()) {
+		switch dt := destType.(type) {
+		case *types.Slice:
+			if st, ok := src.typ.Underlying().(*types.Slice); ok {
+				child, err := r.chainNodes("mapped", "v", dt.Elem(), st.Elem(), steps)
+				if err != nil {
+					return nil, err
 				}
+				return []codeNode{{Kind: nodeKindSliceMap, Src: src.expr, Dest: destExpr, Nil: r.g.collectionNil(opts), DestType: types.TypeString(dt, r.g.qualifier), ElemType: types.TypeString(dt.Elem(), r.g.qualifier), Children: child, LoopWithError: child[0].WithError}}, nil
 			}
-			if !resolved {
-				for idx, p := range params {
-					if idx == ctxIndex {
-						continue
-					}
-					pt := sig.Params().At(idx).Type()
-					if types.Identical(pt, df.Type()) {
-						plans = append(plans, AssignmentPlan{DestField: fname, Nodes: []codeNode{{Kind: nodeKindAssignDirect, Dest: prefixDest(destPtr) + fname, Src: p.Name}}})
-						resolved = true
-						break
-					}
+		case *types.Map:
+			if st, ok := src.typ.Underlying().(*types.Map); ok && types.Identical(st.Key(), dt.Key()) {
+				child, err := r.chainNodes("mapped", "v", dt.Elem(), st.Elem(), steps)
+				if err != nil {
+					return nil, err
 				}
+				return []codeNode{{Kind: nodeKindMapMap, Src: src.expr, Dest: destExpr, Nil: r.g.collectionNil(opts), DestType: types.TypeString(dt, r.g.qualifier), ElemType: types.TypeString(dt.Elem(), r.g.qualifier), Children: child, LoopWithError: child[0].WithError}}, nil
 			}
-			if !resolved {
-				plans = append(plans, AssignmentPlan{DestField: fname, Nodes: []codeNode{{Kind: nodeKindComment, Comment: "no source for " + fname}}})
+		}
+	}
+	return r.chainNodes(destExpr, src.expr, destType, src.typ, steps)
+}
+
+// chainNodes type-checks a mapfn chain from srcType to destType and emits it
+// as a single call or a funcChain node. A result of a different named type
+// with the same underlying type is converted.
+func (r *fieldResolver) chainNodes(destExpr, srcExpr string, destType, srcType types.Type, steps []mapfnStep) ([]codeNode, error) {
+	cur := srcType
+	var children []codeNode
+	withErr := false
+	for _, s := range steps {
+		if param := s.param(); !types.AssignableTo(cur, param) {
+			return nil, fmt.Errorf("mapfn %s: cannot use %s as %s", s.call, types.TypeString(cur, r.g.qualifier), types.TypeString(param, r.g.qualifier))
+		}
+		hasErr := s.sig.Results().Len() == 2
+		children = append(children, codeNode{Kind: nodeKindChainStep, Method: s.call, WithError: hasErr, UseContext: s.sig.Params().Len() == 2, OnEnv: s.env})
+		withErr = withErr || hasErr
+		cur = s.sig.Results().At(0).Type()
+	}
+	cast := ""
+	if !types.AssignableTo(cur, destType) {
+		if !types.Identical(cur.Underlying(), destType.Underlying()) {
+			return nil, fmt.Errorf("mapfn result %s is not assignable to %s", types.TypeString(cur, r.g.qualifier), types.TypeString(destType, r.g.qualifier))
+		}
+		cast = types.TypeString(destType, r.g.qualifier)
+	}
+	if len(children) == 1 && cast == "" {
+		return []codeNode{{Kind: nodeKindAssignFunc, Dest: destExpr, Method: children[0].Method, Arg: srcExpr, WithError: withErr, UseContext: children[0].UseContext, OnEnv: children[0].OnEnv}}, nil
+	}
+	return []codeNode{{Kind: nodeKindFuncChain, Dest: destExpr, Src: srcExpr, CastType: cast, Children: children, WithError: withErr}}, nil
+}
+
+// defaultNodes type-checks a mapdefault value against the destination field
+// and returns the assignment used when no non-zero source is available. The
+// value must be a constant expression in the scope of the field declaration
+// (a literal, or a named constant such as status.Active); strings are written
+// as quoted Go literals.
+func (r *fieldResolver) defaultNodes(destExpr string, df *types.Var, raw string) ([]codeNode, error) {
+	g := r.g
+	// evaluated in the scope of the field declaration, so that file imports
+	// resolve; the assignment in exprNodes verifies representability (e.g.
+	// 300 for int8) as well.
+	pos := g.scopePos(df)
+	tv, err := types.Eval(g.fset, g.pkg, pos, raw)
+	b, _ := df.Type().Underlying().(*types.Basic)
+	switch {
+	case err != nil && b != nil && b.Info()&types.IsString != 0:
+		return nil, fmt.Errorf("mapdefault %q: %w (string values are quoted Go literals)", raw, err)
+	case err != nil:
+		return nil, fmt.Errorf("mapdefault %q: %w", raw, err)
+	case tv.Value == nil:
+		return nil, fmt.Errorf("mapdefault %q is not a constant", raw)
+	}
+	nodes, err := r.exprNodes(destExpr, df, raw, nil, pos)
+	if err != nil {
+		return nil, fmt.Errorf("mapdefault %q: %w", raw, err)
+	}
+	return nodes, nil
+}
+
+// exprParam is a variable in scope of a destination field expression. When
+// emit is set, references are renamed to it in the generated code.
+type exprParam struct {
+	name string
+	typ  types.Type
+	emit string
+}
+
+// exprNodes type-checks a Go expression for a destination field in the scope
+// of the mapping function's parameters (resolved at pos, so file imports and
+// package declarations are visible) and returns a verbatim assignment.
+func (r *fieldResolver) exprNodes(destExpr string, df *types.Var, expr string, scope []exprParam, pos token.Pos) ([]codeNode, error) {
+	out, err := r.g.checkExpr(expr, df.Type(), scope, pos)
+	if err != nil {
+		return nil, err
+	}
+	return []codeNode{{Kind: nodeKindAssignDirect, Dest: destExpr, Src: out}}, nil
+}
+
+// checkExpr type-checks expr as a value assignable to typ with scope in
+// effect at pos, and returns it as written in the generated file: packages it
+// names are imported (under their alias) and scope variables are renamed.
+func (g *generator) checkExpr(expr string, typ types.Type, scope []exprParam, pos token.Pos) (string, error) {
+	ps := make([]string, 0, len(scope))
+	for _, p := range scope {
+		ps = append(ps, p.name+" "+types.TypeString(p.typ, g.sourceQualifier))
+	}
+	src := "func(" + strings.Join(ps, ", ") + ") { var _ " + types.TypeString(typ, g.sourceQualifier) + " = " + expr + " }"
+	lit, err := parser.ParseExprFrom(g.fset, "mapexpr", src, 0)
+	if err != nil {
+		return "", fmt.Errorf("expression %q: %w", expr, err)
+	}
+	info := &types.Info{Defs: map[*ast.Ident]types.Object{}, Uses: map[*ast.Ident]types.Object{}}
+	if err := types.CheckExpr(g.fset, g.pkg, pos, lit, info); err != nil {
+		return "", fmt.Errorf("expression %q: %w", expr, err)
+	}
+	fn := lit.(*ast.FuncLit)
+	renames := map[types.Object]string{}
+	for i, field := range fn.Type.Params.List {
+		if emit := scope[i].emit; emit != "" {
+			renames[info.Defs[field.Names[0]]] = emit
+		}
+	}
+	renamed := false
+	for id, obj := range info.Uses {
+		if pn, ok := obj.(*types.PkgName); ok {
+			if alias := g.addImport(pn.Imported().Path(), pn.Name()); alias != pn.Name() {
+				renames[obj] = alias
 			}
-			continue
 		}
+		if to, ok := renames[obj]; ok {
+			id.Name = to
+			renamed = true
+		}
+	}
+	if renamed {
+		val := fn.Body.List[0].(*ast.DeclStmt).Decl.(*ast.GenDecl).Specs[0].(*ast.ValueSpec).Values[0]
+		var buf bytes.Buffer
+		if err := format.Node(&buf, g.fset, val); err != nil {
+			return "", err
+		}
+		expr = buf.String()
+	}
+	return expr, nil
+}
 
-		nodes := r.g.buildAssignmentNodes(prefixDest(destPtr)+fname, fmt.Sprintf("%s.%s", srcParamName, sf.Name()), df.Type(), sf.Type(), mp.name, useCtx)
-		plans = append(plans, AssignmentPlan{DestField: fname, Nodes: nodes})
+// fallbackNodes builds an if/else-if chain assigning the first non-zero
+// candidate to destExpr, ending in defNodes (if any) when all candidates are
+// zero. Pointer candidates that only map through their pointee are
+// dereferenced and skipped when nil. Every candidate must map to destType,
+// otherwise generation fails.
+func (r *fieldResolver) fallbackNodes(destExpr string, destType types.Type, cands []sourcePath, defNodes []codeNode, opts assignOpts) ([]codeNode, error) {
+	var branches []codeNode
+	for i, c := range cands {
+		conds := append([]string(nil), c.guards...)
+		nodes, err := r.g.buildAssignmentNodes(destExpr, c.expr, destType, c.typ, opts)
+		if err != nil {
+			return nil, err
+		}
+		deref := false
+		if pt, ok := c.typ.(*types.Pointer); ok && hasUnsupported(nodes) {
+			if nodes, err = r.g.buildAssignmentNodes(destExpr, "*"+c.expr, destType, pt.Elem(), opts); err != nil {
+				return nil, err
+			}
+			conds = append(conds, c.expr+" != nil")
+			deref = true
+		}
+		if hasUnsupported(nodes) {
+			return nil, fmt.Errorf("mapsrc candidate %s (%s) cannot be mapped to %s", c.expr, types.TypeString(c.typ, r.g.qualifier), types.TypeString(destType, r.g.qualifier))
+		}
+		if !deref && (i < len(cands)-1 || defNodes != nil) {
+			check, err := r.g.nonZeroCheck(c.expr, c.typ)
+			if err != nil {
+				return nil, err
+			}
+			conds = append(conds, check)
+		}
+		branches = append(branches, codeNode{Kind: nodeKindBranch, Expr: strings.Join(conds, " && "), Children: nodes})
+		if len(conds) == 0 {
+			// unconditional branch terminates the chain
+			return []codeNode{{Kind: nodeKindCond, Dest: destExpr, Children: branches}}, nil
+		}
+	}
+	if defNodes != nil {
+		branches = append(branches, codeNode{Kind: nodeKindBranch, Children: defNodes})
 	}
+	return []codeNode{{Kind: nodeKindCond, Dest: destExpr, Children: branches}}, nil
+}
 
-	return plans, nil
+// hasErrorNode reports whether any node in the tree returns an error.
+func hasErrorNode(nodes []codeNode) bool {
+	for i := range nodes {
+		if nodes[i].WithError || nodes[i].LoopWithError || hasErrorNode(nodes[i].Children) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasNodeKind reports whether nodes contain a node of the given kind.
+func hasNodeKind(nodes []codeNode, kind string) bool {
+	for i := range nodes {
+		if nodes[i].Kind == kind || hasNodeKind(nodes[i].Children, kind) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasUnsupported reports whether any node in the tree is an unsupported mapping.
+func hasUnsupported(nodes []codeNode) bool {
+	for i := range nodes {
+		if nodes[i].Kind == nodeKindUnsupported || hasUnsupported(nodes[i].Children) {
+			return true
+		}
+	}
+	return false
 }
@@ -22,12 +22,28 @@ const (
 	tmplNodeAssignHelper = "assignHelper"
 	tmplNodeAssignMethod = "assignMethod"
 	tmplNodeAssignFunc   = "assignFunc"
+	tmplNodeFuncChain    = "funcChain"
 	tmplNodeSliceMap     = "sliceMap"
 	tmplNodeArrayMap     = "arrayMap"
+	tmplNodeArrayToSlice = "arrayToSlice"
 	tmplNodeMapMap       = "mapMap"
+	tmplNodeSliceToMap   = "sliceToMap"
+	tmplNodeMapToSlice   = "mapToSlice"
+	tmplNodeSetToSlice   = "setToSlice"
+	tmplNodeSliceToSet   = "sliceToSet"
+	tmplNodeReduce       = "reduce"
+	tmplNodeTypeSwitch   = "typeSwitch"
+	tmplNodeAnyMapLoad   = "anyMapLoad"
+	tmplNodeValuesLoad   = "valuesLoad"
+	tmplNodeRecordHeader = "recordHeader"
+	tmplNodeRecordLen    = "recordLen"
+	tmplNodeRecordLoad   = "recordLoad"
+	tmplNodeRecordAny    = "recordAny"
 	tmplNodePtrStructMap = "ptrStructMap"
 	tmplNodePtrMethodMap = "ptrMethodMap"
 	tmplNodePtrFuncMap   = "ptrFuncMap"
+	tmplNodeAdaptCall    = "adaptCall"
+	tmplNodeCond         = "cond"
 	tmplNodeReturn       = "return"
 	tmplNodeUnsupported  = "unsupported"
 )
@@ -72,12 +88,28 @@ func validateTemplates() error {
 		tmplNodeAssignHelper,
 		tmplNodeAssignMethod,
 		tmplNodeAssignFunc,
+		tmplNodeFuncChain,
 		tmplNodeSliceMap,
 		tmplNodeArrayMap,
+		tmplNodeArrayToSlice,
 		tmplNodeMapMap,
+		tmplNodeSliceToMap,
+		tmplNodeMapToSlice,
+		tmplNodeSetToSlice,
+		tmplNodeSliceToSet,
+		tmplNodeReduce,
+		tmplNodeTypeSwitch,
+		tmplNodeAnyMapLoad,
+		tmplNodeValuesLoad,
+		tmplNodeRecordHeader,
+		tmplNodeRecordLen,
+		tmplNodeRecordLoad,
+		tmplNodeRecordAny,
 		tmplNodePtrStructMap,
 		tmplNodePtrMethodMap,
 		tmplNodePtrFuncMap,
+		tmplNodeAdaptCall,
+		tmplNodeCond,
 		tmplNodeReturn,
 		tmplNodeUnsupported,
 	}
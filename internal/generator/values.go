@@ -0,0 +1,158 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"net/textproto"
+	"strconv"
+)
+
+// isValuesMap reports whether t is a map[string][]string such as url.Values
+// or http.Header.
+func isValuesMap(t types.Type) bool {
+	m, ok := t.Underlying().(*types.Map)
+	if !ok {
+		return false
+	}
+	k, ok := m.Key().Underlying().(*types.Basic)
+	if !ok || k.Kind() != types.String {
+		return false
+	}
+	s, ok := m.Elem().Underlying().(*types.Slice)
+	if !ok {
+		return false
+	}
+	e, ok := s.Elem().Underlying().(*types.Basic)
+	return ok && e.Kind() == types.String
+}
+
+// valuesPair reports whether srcType is a map[string][]string read into the
+// struct (or pointer to one) destType.
+func valuesPair(srcType, destType types.Type) bool {
+	s, _ := underlyingStruct(destType)
+	return s != nil && isValuesMap(srcType)
+}
+
+// isHeader reports whether t is http.Header, whose keys are canonicalized.
+func isHeader(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "net/http" && named.Obj().Name() == "Header"
+}
+
+// hasUnmarshalText reports whether *t implements encoding.TextUnmarshaler.
+func hasUnmarshalText(t types.Type) bool {
+	obj, _, _ := types.LookupFieldOrMethod(types.NewPointer(t), false, nil, "UnmarshalText")
+	fn, ok := obj.(*types.Func)
+	if !ok {
+		return false
+	}
+	sig := fn.Type().(*types.Signature)
+	if sig.Params().Len() != 1 || sig.Results().Len() != 1 {
+		return false
+	}
+	p, ok := sig.Params().At(0).Type().(*types.Slice)
+	return ok && types.Identical(p.Elem(), types.Typ[types.Byte]) && types.Identical(sig.Results().At(0).Type(), types.Universe.Lookup("error").Type())
+}
+
+// valueParse fills in how n parses a single string value into t: with
+// UnmarshalText, time.ParseDuration, a strconv function or a plain cast.
+func (g *generator) valueParse(n *codeNode, t types.Type) error {
+	typ := types.TypeString(t, g.qualifier)
+	if hasUnmarshalText(t) {
+		n.Parse, n.UnderType = parseText, typ
+		return nil
+	}
+	if named, ok := t.(*types.Named); ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "time" && named.Obj().Name() == "Duration" {
+		g.addImport("time", "time")
+		n.Parse, n.Method = parseCall, "time.ParseDuration"
+		return nil
+	}
+	b, ok := t.Underlying().(*types.Basic)
+	if !ok {
+		return fmt.Errorf("cannot parse %s from a string", typ)
+	}
+	// call parses through a strconv function returning result.
+	call := func(fn, args string, result types.Type) {
+		g.addImport("strconv", "strconv")
+		n.Parse, n.Method, n.Arg = parseCall, "strconv."+fn, args
+		if !types.Identical(result, t) {
+			n.CastType = typ
+		}
+	}
+	bits := map[types.BasicKind]string{
+		types.Int8: "8", types.Int16: "16", types.Int32: "32", types.Int64: "64",
+		types.Uint8: "8", types.Uint16: "16", types.Uint32: "32", types.Uint64: "64",
+		types.Float32: "32", types.Float64: "64",
+	}[b.Kind()]
+	if bits == "" {
+		bits = "0"
+	}
+	switch {
+	case b.Info()&types.IsString != 0:
+		n.Parse = parseCast
+		if !types.Identical(t, types.Typ[types.String]) {
+			n.CastType = typ
+		}
+	case b.Info()&types.IsBoolean != 0:
+		call("ParseBool", "", types.Typ[types.Bool])
+	case b.Kind() == types.Int:
+		call("Atoi", "", types.Typ[types.Int])
+	case b.Info()&types.IsUnsigned != 0:
+		call("ParseUint", ", 10, "+bits, types.Typ[types.Uint64])
+	case b.Info()&types.IsInteger != 0:
+		call("ParseInt", ", 10, "+bits, types.Typ[types.Int64])
+	case b.Info()&types.IsFloat != 0:
+		call("ParseFloat", ", "+bits, types.Typ[types.Float64])
+	default:
+		return fmt.Errorf("cannot parse %s from a string", typ)
+	}
+	return nil
+}
+
+// how a valuesLoad node parses a value.
+const (
+	parseCast = "cast" // string kinds: converted (CastType) or taken as is
+	parseCall = "call" // Method(value Arg) returning the value and an error
+	parseText = "text" // UnmarshalText into a UnderType value
+)
+
+// valuesBody builds the body of a helper reading a map[string][]string (such
+// as url.Values or http.Header, whose keys are matched canonicalized) into a
+// struct. Fields take the first value of their key and slices every value,
+// parsed by the field type; missing keys leave fields zero and parse errors
+// name the key.
+func (g *generator) valuesBody(plan helperPlan) ([]codeNode, bool, error) {
+	var body []codeNode
+	s, ptr := underlyingStruct(plan.destType)
+	if ptr {
+		body = append(body, codeNode{Kind: nodeKindDestInitAlloc, Var: "dst", UnderType: types.TypeString(plan.destType.(*types.Pointer).Elem(), g.qualifier)})
+	} else {
+		body = append(body, codeNode{Kind: nodeKindDestInit, Var: "dst", DestType: types.TypeString(plan.destType, g.qualifier)})
+	}
+	for i := 0; i < s.NumFields(); i++ {
+		f := s.Field(i)
+		key, _, skip := fieldKey(s, i, plan.keyTag)
+		if !f.Exported() || skip {
+			continue
+		}
+		if isHeader(plan.srcType) {
+			key = textproto.CanonicalMIMEHeaderKey(key)
+		}
+		n := codeNode{Kind: nodeKindValuesLoad, Dest: "dst." + f.Name(), Src: "in", Expr: strconv.Quote(key), Var: "vs[0]", Label: "key %q", WithError: true}
+		t := f.Type()
+		if sl, ok := t.Underlying().(*types.Slice); ok && !hasUnmarshalText(t) {
+			n.ElemType, n.Var, t = types.TypeString(f.Type(), g.qualifier), "s", sl.Elem()
+		}
+		if pt, ok := t.(*types.Pointer); ok {
+			n.Ref, t = "&", pt.Elem()
+		}
+		if err := g.valueParse(&n, t); err != nil {
+			return nil, false, fmt.Errorf("helper %s: field %s: %w", plan.name, f.Name(), err)
+		}
+		if n.Parse != parseCast {
+			g.addImport("fmt", "fmt")
+		}
+		body = append(body, n)
+	}
+	return append(body, codeNode{Kind: nodeKindReturn, Expr: "dst", WithError: true}), true, nil
+}
@@ -0,0 +1,218 @@
+package generator
+
+import (
+	"cmp"
+	"fmt"
+	"go/token"
+	"go/types"
+	"strings"
+)
+
+// unknown-variant policies of a type switch (mapunknown); any other value
+// names an error variable wrapped into the returned error.
+const (
+	unknownError = "error"
+	unknownSkip  = "skip"
+)
+
+// variantsAuto lists every implementation of an interface as its variants
+// (mapvariants, //graft:variants).
+const variantsAuto = "auto"
+
+// variant is a concrete type an interface value may hold, with the variant
+// of a destination interface it was explicitly paired with (if any).
+type variant struct {
+	typ  types.Type
+	pair types.Type
+}
+
+// variantTypes returns the concrete types an interface-typed source may hold:
+// the types listed by the field's mapvariants tag (raw), else by a
+// //graft:variants directive on the interface declaration. Listed types may
+// name their destination counterpart ("Src=Dst"). The "auto" list discovers
+// every named type of the mapped package and the interface's package
+// implementing it (by value, or else by pointer), skipping the empty
+// interface and predeclared interfaces such as error.
+func (g *generator) variantTypes(iface types.Type, raw string, pos token.Pos) ([]variant, error) {
+	it, _ := iface.Underlying().(*types.Interface)
+	named, _ := iface.(*types.Named)
+	if raw == "" && named != nil {
+		for _, d := range g.directives[named.Obj().Pos()] {
+			if d.name == "variants" {
+				raw, pos = strings.Join(strings.Fields(d.args), ","), g.scopePos(named.Obj())
+			}
+		}
+	}
+	if raw == "" {
+		return nil, nil
+	}
+	if raw != variantsAuto {
+		var variants []variant
+		evalType := func(name string) (types.Type, error) {
+			tv, err := types.Eval(g.fset, g.pkg, pos, strings.TrimSpace(name))
+			if err != nil || !tv.IsType() {
+				return nil, fmt.Errorf("variant %s is not a type", name)
+			}
+			return tv.Type, nil
+		}
+		for _, entry := range strings.Split(raw, ",") {
+			name, pair, paired := strings.Cut(entry, "=")
+			t, err := evalType(name)
+			if err != nil {
+				return nil, err
+			}
+			if !types.Implements(t, it) {
+				return nil, fmt.Errorf("variant %s does not implement %s", name, types.TypeString(iface, g.qualifier))
+			}
+			v := variant{typ: t}
+			if paired {
+				if v.pair, err = evalType(pair); err != nil {
+					return nil, err
+				}
+			}
+			variants = append(variants, v)
+		}
+		return variants, nil
+	}
+	if named == nil || named.Obj().Pkg() == nil {
+		return nil, nil
+	}
+	return g.discoverVariants(named, g.pkg, named.Obj().Pkg()), nil
+}
+
+// discoverVariants returns the named types of pkgs (exported ones outside the
+// mapped package) implementing the interface iface, by value or else by
+// pointer. It skips the empty interface.
+func (g *generator) discoverVariants(iface *types.Named, pkgs ...*types.Package) []variant {
+	it, _ := iface.Underlying().(*types.Interface)
+	if it == nil || it.NumMethods() == 0 {
+		return nil
+	}
+	var variants []variant
+	seen := map[types.Type]bool{}
+	for _, pkg := range pkgs {
+		for _, name := range pkg.Scope().Names() {
+			tn, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+			if !ok || tn.IsAlias() || (pkg != g.pkg && !tn.Exported()) {
+				continue
+			}
+			t, ok := tn.Type().(*types.Named)
+			if !ok || seen[t] || t.TypeParams().Len() > 0 || types.IsInterface(t) {
+				continue
+			}
+			seen[t] = true
+			switch {
+			case types.Implements(t, it):
+				variants = append(variants, variant{typ: t})
+			case types.Implements(types.NewPointer(t), it):
+				variants = append(variants, variant{typ: types.NewPointer(t)})
+			}
+		}
+	}
+	return variants
+}
+
+// variantName is the name variants of two interfaces are paired by: the type
+// name without pointers, after the last underscore (protobuf oneof wrappers
+// are named Message_Field).
+func variantName(t types.Type) string {
+	if pt, ok := t.(*types.Pointer); ok {
+		t = pt.Elem()
+	}
+	named, ok := t.(*types.Named)
+	if !ok {
+		return ""
+	}
+	name := named.Obj().Name()
+	return name[strings.LastIndex(name, "_")+1:]
+}
+
+// pairVariant returns the variant of the destination interface destType that
+// v converts to: its explicit pair, else the single target variant of the
+// same name among the listed ones (or those of the interface's package).
+func (g *generator) pairVariant(v variant, destType types.Type) (types.Type, error) {
+	if v.pair != nil {
+		return v.pair, nil
+	}
+	targets, err := g.variantTypes(destType, "", token.NoPos)
+	if err != nil {
+		return nil, err
+	}
+	// interfaces without a list, such as generated oneofs, pair with the
+	// implementations of their own package.
+	if named, ok := destType.(*types.Named); ok && targets == nil && named.Obj().Pkg() != nil {
+		targets = g.discoverVariants(named, named.Obj().Pkg())
+	}
+	var match types.Type
+	for _, t := range targets {
+		if variantName(t.typ) != variantName(v.typ) {
+			continue
+		}
+		if match != nil {
+			return nil, fmt.Errorf("variant %s: ambiguous counterparts %s and %s in %s", types.TypeString(v.typ, g.qualifier), types.TypeString(match, g.qualifier), types.TypeString(t.typ, g.qualifier), types.TypeString(destType, g.qualifier))
+		}
+		match = t.typ
+	}
+	if match == nil {
+		return nil, fmt.Errorf("variant %s has no counterpart in %s", types.TypeString(v.typ, g.qualifier), types.TypeString(destType, g.qualifier))
+	}
+	return match, nil
+}
+
+// typeSwitchNodes maps an interface-typed source by switching on its dynamic
+// type: every variant converts like a field of that type, or, for a
+// destination interface, to its paired destination variant. A nil source
+// leaves the destination unchanged and other types are handled by the
+// opts.unknown policy (an error by default). It returns nil when the source
+// has no variants.
+func (g *generator) typeSwitchNodes(destExpr, srcExpr string, destType, srcType types.Type, opts assignOpts) ([]codeNode, error) {
+	variants, err := g.variantTypes(srcType, opts.variants, opts.pos)
+	if err != nil || len(variants) == 0 {
+		return nil, err
+	}
+	loop := g.enterLoop()
+	defer func() { g.loopDepth-- }()
+	n := codeNode{Kind: nodeKindTypeSwitch, Src: srcExpr, Dest: destExpr, Loop: loop}
+	for _, v := range variants {
+		c := codeNode{Kind: nodeKindTypeCase, CastType: types.TypeString(v.typ, g.qualifier)}
+		target := destType
+		if types.IsInterface(destType) && (v.pair != nil || !types.AssignableTo(v.typ, destType)) {
+			if target, err = g.pairVariant(v, destType); err != nil {
+				return nil, err
+			}
+			if !types.AssignableTo(target, destType) {
+				return nil, fmt.Errorf("variant %s: %s does not implement %s", c.CastType, types.TypeString(target, g.qualifier), types.TypeString(destType, g.qualifier))
+			}
+			c.ElemType = types.TypeString(target, g.qualifier)
+		}
+		dest := destExpr
+		if c.ElemType != "" {
+			dest = "mapped" + loop
+		}
+		if c.Children, err = g.buildAssignmentNodes(dest, "v"+loop, target, v.typ, opts); err != nil {
+			return nil, err
+		}
+		if hasUnsupported(c.Children) {
+			return nil, fmt.Errorf("variant %s: no mapping to %s", c.CastType, types.TypeString(target, g.qualifier))
+		}
+		n.Children = append(n.Children, c)
+	}
+	path := cmp.Or(opts.path, types.TypeString(srcType, g.sourceQualifier))
+	switch opts.unknown {
+	case "", unknownError:
+		n.Expr = fmt.Sprintf(`fmt.Errorf("mapping %s: unexpected type %%T", v%s)`, path, loop)
+	case unknownSkip:
+	default:
+		errValue, err := g.checkExpr(opts.unknown, types.Universe.Lookup("error").Type(), nil, opts.pos)
+		if err != nil {
+			return nil, fmt.Errorf("mapunknown %s: want error, skip or an error value", opts.unknown)
+		}
+		n.Expr = fmt.Sprintf(`fmt.Errorf("mapping %s: %%w: %%T", %s, v%s)`, path, errValue, loop)
+	}
+	if n.Expr != "" {
+		g.addImport("fmt", "fmt")
+		n.WithError = true
+	}
+	n.LoopWithError = hasErrorNode(n.Children)
+	return []codeNode{n}, nil
+}